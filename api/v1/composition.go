@@ -1,7 +1,10 @@
 package v1
 
 import (
+	"strconv"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // +kubebuilder:object:root=true
@@ -45,6 +48,17 @@ type CompositionSpec struct {
 	// A set of environment variables that will be made available inside the synthesis Pod.
 	// +kubebuilder:validation:MaxItems:=500
 	SynthesisEnv []EnvVar `json:"synthesisEnv,omitempty"`
+
+	// Values are arbitrary structured parameters passed to the synthesizer as the functionConfig of its input ResourceList.
+	// They can be decoded using the helpers in the pkg/function package.
+	Values *runtime.RawExtension `json:"values,omitempty"`
+
+	// Priority determines the order in which compositions are synthesized when more syntheses are pending than can run concurrently.
+	// Higher values are synthesized first.
+	//
+	// Compositions with a positive priority can also use the synthesis slots reserved by the controller's --reserved-concurrency flag.
+	// Like any other spec change, modifying the priority causes the composition to be resynthesized.
+	Priority int32 `json:"priority,omitempty"`
 }
 
 type CompositionStatus struct {
@@ -90,9 +104,13 @@ type Synthesis struct {
 	// Time at which the synthesis's reconciled resources became ready.
 	Ready *metav1.Time `json:"ready,omitempty"`
 
-	// Counter used internally to calculate back off when retrying failed syntheses.
+	// Number of synthesizer pods (or requests to service-backed synthesizers) used by this synthesis.
+	// Used to calculate back off when retrying failed syntheses, and bounded by the synthesizer's maxAttempts.
 	Attempts int `json:"attempts,omitempty"`
 
+	// Number of times the synthesizer has failed during this synthesis, including restarts within the same attempt.
+	Failures int `json:"failures,omitempty"`
+
 	// References to every resource slice that contains the resources comprising this synthesis.
 	// Immutable.
 	ResourceSlices []*ResourceSliceRef `json:"resourceSlices,omitempty"`
@@ -103,9 +121,32 @@ type Synthesis struct {
 	// InputRevisions contains the versions of the input resources that were used for this synthesis.
 	InputRevisions []InputRevisions `json:"inputRevisions,omitempty"`
 
+	// EnvRevisions contains the versions of the Secrets and ConfigMaps referenced by synthesisEnv that were used for this synthesis.
+	// Keyed by the name of the environment variable.
+	EnvRevisions []InputRevisions `json:"envRevisions,omitempty"`
+
 	// Deferred is true when this synthesis was caused by a change to either the synthesizer
 	// or an input with a ref that sets `Defer == true`.
 	Deferred bool `json:"deferred,omitempty"`
+
+	// InputHash identifies the synthesizer generation, composition spec, and input contents used by this synthesis.
+	InputHash string `json:"inputHash,omitempty"`
+
+	// CacheHit is true when the synthesizer wasn't executed because the previous synthesis has the same InputHash.
+	// Cache hits reference the resource slices of the previous synthesis, but keep their own UUID.
+	CacheHit bool `json:"cacheHit,omitempty"`
+
+	// Steps records the execution of each step of the pipeline when the synthesizer declares functions.
+	Steps []SynthesisStep `json:"steps,omitempty"`
+}
+
+// SynthesisStep describes a completed step of a synthesis pipeline.
+type SynthesisStep struct {
+	// Name of the function, or "synthesizer" for the synthesizer's own command.
+	Name string `json:"name,omitempty"`
+
+	// Duration is the time taken by the step's command.
+	Duration metav1.Duration `json:"duration,omitempty"`
 }
 
 type Result struct {
@@ -243,3 +284,16 @@ func (c *Composition) ShouldForceResynthesis() bool {
 	val, ok := c.GetAnnotations()[forceResynthesisAnnotation]
 	return ok && val == c.Status.GetCurrentSynthesisUUID()
 }
+
+// ResynthesisForced returns true when the current synthesis was dispatched because ForceResynthesis was called on the previous one.
+func (c *Composition) ResynthesisForced() bool {
+	val, ok := c.GetAnnotations()[forceResynthesisAnnotation]
+	return ok && c.Status.PreviousSynthesis != nil && val == c.Status.PreviousSynthesis.UUID
+}
+
+const approvedSynthesizerGenerationAnnotation = "eno.azure.io/approved-synthesizer-generation"
+
+// RolloutApproved returns true when the composition has been individually approved to receive the given synthesizer generation.
+func (c *Composition) RolloutApproved(synth *Synthesizer) bool {
+	return c.GetAnnotations()[approvedSynthesizerGenerationAnnotation] == strconv.FormatInt(synth.Generation, 10)
+}
@@ -420,6 +420,9 @@ func TestForceSynthesisAnnotation(t *testing.T) {
 	assert.True(t, comp.ShouldForceResynthesis())
 
 	// Update the synthesis UUID
-	comp.Status.CurrentSynthesis.UUID = "234"
+	assert.False(t, comp.ResynthesisForced())
+	comp.Status.PreviousSynthesis = comp.Status.CurrentSynthesis
+	comp.Status.CurrentSynthesis = &Synthesis{UUID: "234"}
 	assert.False(t, comp.ShouldForceResynthesis())
+	assert.True(t, comp.ResynthesisForced())
 }
@@ -1,9 +1,27 @@
 package v1
 
+import corev1 "k8s.io/api/core/v1"
+
 // +kubebuilder:validation:XValidation:message="name must match [a-zA-Z_][a-zA-Z0-9_]*",rule="self.name.matches('^[a-zA-Z_][a-zA-Z0-9_]*$')"
+// +kubebuilder:validation:XValidation:message="value and valueFrom are mutually exclusive",rule="!has(self.value) || !has(self.valueFrom)"
 type EnvVar struct {
 	// +required
 	// +kubebuilder:validation:MaxLength:=1000
 	Name  string `json:"name"`
 	Value string `json:"value,omitempty"`
+
+	// ValueFrom sources the value from a key of a Secret or ConfigMap in the composition's namespace.
+	// These are resolved by the kubelet, so they're only supported for compositions in the synthesizer pod namespace.
+	// Not supported by service-backed synthesizers.
+	ValueFrom *EnvVarSource `json:"valueFrom,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:message="exactly one of secretKeyRef or configMapKeyRef must be set",rule="has(self.secretKeyRef) != has(self.configMapKeyRef)"
+type EnvVarSource struct {
+	SecretKeyRef    *corev1.SecretKeySelector    `json:"secretKeyRef,omitempty"`
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// Resynthesize the composition when the referenced object is modified.
+	// Requires the controller's --watch-synthesis-env-refs flag.
+	Resynthesize bool `json:"resynthesize,omitempty"`
 }
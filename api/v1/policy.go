@@ -0,0 +1,69 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+type PolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Policy `json:"items"`
+}
+
+// Policies are guardrails for the resources produced by synthesizers.
+//
+// Every rule of every policy is evaluated against each resource produced by a synthesis before it's written to resource slices.
+// Resources that violate deny rules fail the synthesis, while warn rules only add warnings to the synthesis results.
+//
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+type Policy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PolicySpec `json:"spec,omitempty"`
+}
+
+type PolicySpec struct {
+	// +kubebuilder:validation:MaxItems:=64
+	// +kubebuilder:validation:XValidation:rule="self.all(r, self.exists_one(s, s.name == r.name))",message="rule names must be unique"
+	Rules []PolicyRule `json:"rules,omitempty"`
+}
+
+// PolicyRule is a CEL expression that every synthesized resource is expected to satisfy.
+type PolicyRule struct {
+	// Name identifies the rule in the results of syntheses that violate it.
+	//
+	// +required
+	// +kubebuilder:validation:MaxLength:=60
+	// +kubebuilder:validation:Pattern:=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Expression is a CEL expression that must evaluate to true for every resource.
+	// The resource is available as `self`. Expressions that can't be evaluated for a
+	// resource e.g. because a referenced field doesn't exist are considered to be violated.
+	//
+	// +required
+	Expression string `json:"expression"`
+
+	// Message is included in the results of syntheses that violate the rule.
+	Message string `json:"message,omitempty"`
+
+	// Action determines what happens to syntheses that violate the rule.
+	//
+	// - Deny: the synthesis fails with an error result and its resources aren't written
+	// - Warn: the synthesis is given a warning result
+	//
+	// +kubebuilder:default=Deny
+	// +kubebuilder:validation:Enum=Deny;Warn
+	Action PolicyAction `json:"action,omitempty"`
+}
+
+// PolicyAction determines how violations of a policy rule are handled.
+type PolicyAction string
+
+const (
+	PolicyActionDeny PolicyAction = "Deny"
+	PolicyActionWarn PolicyAction = "Warn"
+)
@@ -1,6 +1,10 @@
 package v1
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // +kubebuilder:object:root=true
 type ResourceSliceList struct {
@@ -24,6 +28,13 @@ type ResourceSliceSpec struct {
 	SynthesisUUID         string     `json:"synthesisUUID,omitempty"`
 	Attempt               int        `json:"attempt,omitempty"`
 	Resources             []Manifest `json:"resources,omitempty"`
+
+	// Allowlist is the synthesizer's allowlist at the time the slice was written.
+	// The reconciler doesn't create or update resources that it doesn't allow.
+	Allowlist *ResourceAllowlist `json:"allowlist,omitempty"`
+
+	// ServerSideApply is the synthesizer's server-side apply configuration at the time the slice was written.
+	ServerSideApply *ServerSideApply `json:"serverSideApply,omitempty"`
 }
 
 type Manifest struct {
@@ -42,6 +53,26 @@ type ResourceState struct {
 	Reconciled bool         `json:"reconciled,omitempty"`
 	Ready      *metav1.Time `json:"ready,omitempty"`
 	Deleted    bool         `json:"deleted,omitempty"`
+
+	// Denied is true when the resource isn't allowed by the allowlist recorded on its slice.
+	// Denied resources are never created or updated by Eno, but can still be deleted.
+	Denied bool `json:"denied,omitempty"`
+
+	// Conflicts lists the fields that prevented the resource from being applied because they're managed by other field managers.
+	// Only reported when server-side apply conflicts are configured to be reported rather than forced.
+	Conflicts []string `json:"conflicts,omitempty"`
+
+	// Drifted is true when the resource's current state differs from its desired state,
+	// but it wasn't updated because it's in observe-only mode.
+	Drifted bool `json:"drifted,omitempty"`
+
+	// DriftedFields lists the paths of (some of) the fields that have drifted, when known.
+	DriftedFields []string `json:"driftedFields,omitempty"`
+
+	// DryRunAction is the action that the reconciler would have taken when running in dry-run mode
+	// i.e. create, update, patch, apply, delete, recreate, or none.
+	// Not set on resources that have already been reconciled by a reconciler that isn't in dry-run mode.
+	DryRunAction string `json:"dryRunAction,omitempty"`
 }
 
 func (r *ResourceState) Equal(rr *ResourceState) bool {
@@ -51,7 +82,10 @@ func (r *ResourceState) Equal(rr *ResourceState) bool {
 	if rr == nil {
 		return false
 	}
-	if r.Reconciled != rr.Reconciled || r.Deleted != rr.Deleted {
+	if r.Reconciled != rr.Reconciled || r.Deleted != rr.Deleted || r.Denied != rr.Denied || !slices.Equal(r.Conflicts, rr.Conflicts) {
+		return false
+	}
+	if r.Drifted != rr.Drifted || !slices.Equal(r.DriftedFields, rr.DriftedFields) || r.DryRunAction != rr.DryRunAction {
 		return false
 	}
 	if r.Ready == nil {
@@ -98,6 +98,57 @@ func TestResourceStateEqual(t *testing.T) {
 				Deleted:    false,
 			},
 		},
+		{
+			Name:     "denied-mismatch",
+			Expected: false,
+			A: &ResourceState{
+				Denied: true,
+			},
+			B: &ResourceState{},
+		},
+		{
+			Name:     "conflicts-mismatch",
+			Expected: false,
+			A: &ResourceState{
+				Conflicts: []string{".data.foo: conflict with \"other\""},
+			},
+			B: &ResourceState{
+				Conflicts: []string{".data.bar: conflict with \"other\""},
+			},
+		},
+		{
+			Name:     "drifted-mismatch",
+			Expected: false,
+			A: &ResourceState{
+				Reconciled: true,
+				Drifted:    true,
+			},
+			B: &ResourceState{
+				Reconciled: true,
+			},
+		},
+		{
+			Name:     "drifted-fields-mismatch",
+			Expected: false,
+			A: &ResourceState{
+				Drifted:       true,
+				DriftedFields: []string{".data.foo"},
+			},
+			B: &ResourceState{
+				Drifted:       true,
+				DriftedFields: []string{".data.bar"},
+			},
+		},
+		{
+			Name:     "dry-run-action-mismatch",
+			Expected: false,
+			A: &ResourceState{
+				DryRunAction: "create",
+			},
+			B: &ResourceState{
+				DryRunAction: "none",
+			},
+		},
 	}
 
 	for _, tt := range tests {
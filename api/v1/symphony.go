@@ -1,6 +1,9 @@
 package v1
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
 
 // +kubebuilder:object:root=true
 type SymphonyList struct {
@@ -39,6 +42,9 @@ type SymphonySpec struct {
 	// Copied opaquely into the compositions managed by this symphony.
 	// +kubebuilder:validation:MaxItems:=500
 	SynthesisEnv []EnvVar `json:"synthesisEnv,omitempty"`
+
+	// Values are inherited by all compositions managed by this symphony.
+	Values *runtime.RawExtension `json:"values,omitempty"`
 }
 
 type SymphonyStatus struct {
@@ -62,4 +68,11 @@ type Variation struct {
 	// Variation-specific bindings get merged with Symphony bindings and take
 	// precedence over them.
 	Bindings []Binding `json:"bindings,omitempty"`
+
+	// Used to populate the composition's spec.priority.
+	Priority int32 `json:"priority,omitempty"`
+
+	// Variation-specific values are deep merged with Symphony values and take
+	// precedence over them.
+	Values *runtime.RawExtension `json:"values,omitempty"`
 }
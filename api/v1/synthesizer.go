@@ -1,8 +1,14 @@
 package v1
 
 import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // +kubebuilder:object:root=true
@@ -30,6 +36,7 @@ type Synthesizer struct {
 }
 
 // +kubebuilder:validation:XValidation:rule="duration(self.execTimeout) <= duration(self.podTimeout)",message="podTimeout must be greater than execTimeout"
+// +kubebuilder:validation:XValidation:rule="!has(self.service) || !has(self.functions)",message="functions are not supported by service-backed synthesizers"
 type SynthesizerSpec struct {
 	// Copied opaquely into the container's image property.
 	Image string `json:"image,omitempty"`
@@ -39,7 +46,17 @@ type SynthesizerSpec struct {
 	// +kubebuilder:default={"synthesize"}
 	Command []string `json:"command,omitempty"`
 
-	// Timeout for each execution of the synthesizer command.
+	// Functions are executed in order after the synthesizer command, forming a pipeline.
+	// Each function is given the ResourceList output by the previous step, and the output
+	// of the last function is the output of the synthesis.
+	//
+	// Every step runs in its own container of the synthesizer pod.
+	//
+	// +kubebuilder:validation:MaxItems:=16
+	// +kubebuilder:validation:XValidation:rule="self.all(f, self.exists_one(g, g.name == f.name))",message="function names must be unique"
+	Functions []SynthesizerFunction `json:"functions,omitempty"`
+
+	// Timeout for each execution of the synthesizer command (and each function, if any).
 	//
 	// +kubebuilder:default="10s"
 	ExecTimeout *metav1.Duration `json:"execTimeout,omitempty"`
@@ -60,6 +77,251 @@ type SynthesizerSpec struct {
 
 	// PodOverrides sets values in the pods used to execute this synthesizer.
 	PodOverrides PodOverrides `json:"podOverrides,omitempty"`
+
+	// Rollout controls how changes to this synthesizer are propagated to the compositions that use it.
+	// By default compositions are resynthesized one at a time, honoring the globally configured cooldown period.
+	Rollout *RolloutStrategy `json:"rollout,omitempty"`
+
+	// ConcurrencyLimit is the maximum number of compositions using this synthesizer that can be synthesized at the same time.
+	// Syntheses are still subject to the controller's global and per-namespace limits.
+	//
+	// +kubebuilder:validation:Minimum:=1
+	ConcurrencyLimit *int `json:"concurrencyLimit,omitempty"`
+
+	// MaxAttempts is the number of times a synthesis can be attempted before it's marked as failed.
+	// Each synthesizer pod (or request to a service-backed synthesizer) is an attempt, i.e. status.currentSynthesis.attempts.
+	// Restarts of the synthesizer process within the same pod aren't counted.
+	// Failed syntheses aren't retried until the composition, its inputs, or the synthesizer are modified.
+	// Retries are unbounded when unset.
+	//
+	// +kubebuilder:validation:Minimum:=1
+	MaxAttempts *int `json:"maxAttempts,omitempty"`
+
+	// Validation checks the synthesized resources before they're written to resource slices.
+	// Syntheses that produce invalid resources fail with an error result for each of them.
+	//
+	// - Schema: resources are validated against the apiserver's OpenAPI schema
+	// - DryRun: resources are applied using server-side dry-run requests, which also runs admission
+	//
+	// Resources aren't validated when unset.
+	//
+	// +kubebuilder:validation:Enum=Schema;DryRun
+	Validation ValidationMode `json:"validation,omitempty"`
+
+	// Allowlist restricts the resources this synthesizer can produce.
+	// Syntheses that produce resources that aren't allowed fail with an error result for each of them.
+	// The allowlist is recorded on the resulting resource slices, and the reconciler refuses to create or update resources it doesn't allow.
+	// Any resource is allowed when unset.
+	Allowlist *ResourceAllowlist `json:"allowlist,omitempty"`
+
+	// ServerSideApply reconciles the synthesized resources using server-side apply with the "eno" field manager,
+	// instead of updating them with the result of a three-way merge. Fields managed by other controllers are left as-is.
+	//
+	// Can be overridden for individual resources using the eno.azure.io/server-side-apply annotation.
+	// Recorded on resource slices, so changes take effect when compositions are resynthesized.
+	ServerSideApply *ServerSideApply `json:"serverSideApply,omitempty"`
+
+	// Service backs the synthesizer with a long-running HTTP service instead of a new pod for every synthesis.
+	// The image, command, podTimeout, and podOverrides are ignored when set.
+	// The execTimeout bounds each request, including connecting to the service, since the controller doesn't set any other timeout.
+	Service *SynthesizerService `json:"service,omitempty"`
+}
+
+// ValidationMode determines how synthesized resources are validated.
+type ValidationMode string
+
+const (
+	ValidationModeSchema ValidationMode = "Schema"
+	ValidationModeDryRun ValidationMode = "DryRun"
+)
+
+type ServerSideApply struct {
+	// Conflicts determines how fields that are managed by other field managers are handled.
+	//
+	// - Force: Eno takes ownership of the conflicting fields
+	// - Report: the resource isn't applied, and the conflicting fields are reported in its resource slice's status
+	//
+	// Can be overridden for individual resources using the eno.azure.io/server-side-apply-conflicts annotation.
+	//
+	// +kubebuilder:default=Force
+	// +kubebuilder:validation:Enum=Force;Report
+	Conflicts ConflictPolicy `json:"conflicts,omitempty"`
+}
+
+// ConflictPolicy determines how server-side apply conflicts are handled.
+type ConflictPolicy string
+
+const (
+	ConflictPolicyForce  ConflictPolicy = "Force"
+	ConflictPolicyReport ConflictPolicy = "Report"
+)
+
+// ResourceAllowlist restricts the resources that can be produced by a synthesizer.
+// Resources must be allowed by both the namespaces and kinds, when set.
+type ResourceAllowlist struct {
+	// Namespaces that resources can be created in. Any namespace is allowed when empty.
+	//
+	// Cluster-scoped resources (i.e. those without a namespace) aren't allowed when namespaces are set,
+	// with the exception of the allowed namespaces themselves.
+	//
+	// +kubebuilder:validation:MaxItems:=256
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Kinds of resources that can be created. Any kind is allowed when empty.
+	//
+	// +kubebuilder:validation:MaxItems:=256
+	Kinds []AllowedKind `json:"kinds,omitempty"`
+}
+
+type AllowedKind struct {
+	// Group of the resource kind e.g. "apps". The core group is represented by an empty string.
+	Group string `json:"group,omitempty"`
+
+	// Kind of the resource e.g. "Deployment", or "*" to allow every kind in the group.
+	//
+	// +required
+	Kind string `json:"kind"`
+}
+
+// Allows returns true when the allowlist permits a resource of the given kind, namespace, and name.
+// Nil allowlists allow any resource.
+func (a *ResourceAllowlist) Allows(gk schema.GroupKind, namespace, name string) bool {
+	if a == nil {
+		return true
+	}
+
+	if len(a.Kinds) > 0 && !slices.ContainsFunc(a.Kinds, func(k AllowedKind) bool {
+		return k.Group == gk.Group && (k.Kind == "*" || k.Kind == gk.Kind)
+	}) {
+		return false
+	}
+
+	if len(a.Namespaces) == 0 {
+		return true
+	}
+	if namespace == "" {
+		return gk == schema.GroupKind{Kind: "Namespace"} && slices.Contains(a.Namespaces, name)
+	}
+	return slices.Contains(a.Namespaces, namespace)
+}
+
+// SynthesizerFunction is a step of a synthesis pipeline. Functions implement the same KRM Functions API as synthesizers.
+type SynthesizerFunction struct {
+	// Name identifies the function in the synthesis status and the results it produces.
+	//
+	// +required
+	// +kubebuilder:validation:MaxLength:=60
+	// +kubebuilder:validation:Pattern:=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Copied opaquely into the container's image property.
+	//
+	// +required
+	Image string `json:"image"`
+
+	// Copied opaquely into the container's command property.
+	//
+	// +kubebuilder:default={"synthesize"}
+	Command []string `json:"command,omitempty"`
+}
+
+// SynthesizerService is a long-running synthesizer. Eno POSTs the same KRM ResourceList (JSON) that
+// command-based synthesizers receive on stdin, and expects the output ResourceList in the response body.
+// Responses with a non-2xx status code are considered to be failed attempts and are retried.
+// Only HTTP(S) endpoints are supported i.e. not gRPC.
+type SynthesizerService struct {
+	// URL of the synthesizer's endpoint e.g. http://my-synth.my-namespace.svc:8080/synthesize
+	//
+	// +required
+	// +kubebuilder:validation:Pattern:=`^https?://`
+	URL string `json:"url"`
+}
+
+// RolloutStrategy breaks the rollout of a synthesizer change into waves.
+//
+// Compositions are assigned to waves using the same deterministic (but per-generation) order
+// used by the default rollout. Every composition in a wave is eligible for resynthesis at once,
+// subject only to the synthesis concurrency limit. The next wave starts after every composition
+// in the previous waves has been synthesized and the previous wave's bake time has elapsed.
+//
+// Compositions not covered by any wave are rolled out in a final, implicit wave.
+type RolloutStrategy struct {
+	Waves []RolloutWave `json:"waves,omitempty"`
+
+	// FailureThreshold is the number of compositions that can fail after receiving a new synthesizer generation
+	// before its rollout is halted. Compositions fail when their synthesis produces an error result, or when they
+	// don't become ready within the ReadyTimeout.
+	//
+	// Halted rollouts will not progress until the synthesizer is modified again.
+	// Halting is disabled when unset.
+	//
+	// +kubebuilder:validation:Minimum:=1
+	FailureThreshold *int `json:"failureThreshold,omitempty"`
+
+	// ReadyTimeout is how long compositions have to become ready after being synthesized with a new synthesizer generation.
+	// Compositions that haven't become ready by then count towards the FailureThreshold.
+	ReadyTimeout *metav1.Duration `json:"readyTimeout,omitempty"`
+
+	// Windows restrict deferred syntheses (synthesizer changes and deferred input changes) to particular periods of time.
+	// Deferred syntheses are dispatched only while at least one window is open. They can be dispatched at any time when unset.
+	//
+	// These windows are honored in addition to any configured on the controller.
+	Windows []TimeWindow `json:"windows,omitempty"`
+
+	// Blackouts are periods of time during which deferred syntheses will not be dispatched, even if a window is open.
+	//
+	// These blackouts are honored in addition to any configured on the controller.
+	Blackouts []TimeWindow `json:"blackouts,omitempty"`
+
+	// RequireApproval gates each wave of the rollout on manual approval. Synthesizers without waves
+	// are rolled out in a single wave.
+	//
+	// Waves are approved by setting the "eno.azure.io/approved-rollout" annotation on the synthesizer
+	// to "{generation}/{count}", which approves the first count waves of the given generation, or just
+	// "{generation}" to approve the entire rollout. Individual compositions can be approved by setting
+	// their "eno.azure.io/approved-synthesizer-generation" annotation to the synthesizer's generation.
+	//
+	// Compositions waiting for approval are listed in status.rollout.awaitingApproval.
+	RequireApproval bool `json:"requireApproval,omitempty"`
+}
+
+// TimeWindow is either a recurring period of time that starts on a cron schedule and lasts for a fixed duration,
+// or a single period of time between a start and end time.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.schedule) == has(self.duration)",message="schedule and duration must be set together"
+// +kubebuilder:validation:XValidation:rule="has(self.start) == has(self.end)",message="start and end must be set together"
+// +kubebuilder:validation:XValidation:rule="has(self.schedule) != has(self.start)",message="exactly one of schedule or start must be set"
+type TimeWindow struct {
+	// Schedule is a standard 5-field cron expression (minute hour day-of-month month day-of-week) evaluated in UTC.
+	// Each time it matches, the window is open for the given duration.
+	//
+	// +kubebuilder:validation:Pattern:=`^\S+ \S+ \S+ \S+ \S+$`
+	Schedule string `json:"schedule,omitempty"`
+
+	// Duration is how long the window stays open after each match of the schedule.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Start is the time at which a one-off window opens.
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// End is the time at which a one-off window closes.
+	End *metav1.Time `json:"end,omitempty"`
+}
+
+// RolloutWave sizes are cumulative i.e. a wave of 25% following a wave of 5% will resynthesize an additional 20% of compositions.
+// When both count and percent are set, the larger of the two is used.
+type RolloutWave struct {
+	// Count is the absolute number of compositions that have received the change by the end of this wave.
+	// +kubebuilder:validation:Minimum:=0
+	Count int `json:"count,omitempty"`
+
+	// Percent is the percentage (0-100) of compositions that have received the change by the end of this wave.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=100
+	Percent int `json:"percent,omitempty"`
+
+	// BakeTime is how long to wait after this wave has been synthesized before starting the next one.
+	BakeTime *metav1.Duration `json:"bakeTime,omitempty"`
 }
 
 type PodOverrides struct {
@@ -67,11 +329,99 @@ type PodOverrides struct {
 	Annotations map[string]string           `json:"annotations,omitempty"`
 	Resources   corev1.ResourceRequirements `json:"resources,omitempty"`
 	Affinity    *corev1.Affinity            `json:"affinity,omitempty"`
+
+	// Tolerations are appended to the toleration configured by the controller's --taint-toleration flag.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	NodeSelector      map[string]string `json:"nodeSelector,omitempty"`
+	PriorityClassName string            `json:"priorityClassName,omitempty"`
+
+	// RuntimeClassName can be used to run synthesizers in a sandbox e.g. gVisor.
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	ImagePullPolicy  corev1.PullPolicy             `json:"imagePullPolicy,omitempty"`
+
+	// Volumes are added to the pod. The "sharedfs" volume is reserved for Eno.
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// VolumeMounts are added to the synthesizer container. Paths equal to or under /eno are reserved for Eno.
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// SecurityContext is merged into the synthesizer container's default security context.
+	// Fields that are set replace the corresponding defaults, so any hardening default can be disabled
+	// e.g. by running as root, in privileged mode, with privilege escalation, or with additional capabilities.
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
 }
 
 type SynthesizerStatus struct {
+	// Rollout reports on the rollout of the synthesizer's current generation.
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+}
+
+type RolloutStatus struct {
+	// The synthesizer generation being rolled out.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Halted is set when the rollout has been halted because it crossed the failure threshold.
+	Halted *metav1.Time `json:"halted,omitempty"`
+
+	// Reason describes why the rollout was halted.
+	Reason string `json:"reason,omitempty"`
+
+	// AwaitingApproval lists compositions that would receive the current generation if their wave of the rollout
+	// was approved. Only the first 100 are listed, in rollout order.
+	AwaitingApproval []CompositionRef `json:"awaitingApproval,omitempty"`
+
+	// AwaitingApprovalCount is the total number of compositions waiting for approval.
+	AwaitingApprovalCount int `json:"awaitingApprovalCount,omitempty"`
+}
+
+type CompositionRef struct {
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// RolloutHalted returns true when the rollout of the synthesizer's current generation has been halted.
+func (s *Synthesizer) RolloutHalted() bool {
+	r := s.Status.Rollout
+	return r != nil && r.Halted != nil && r.ObservedGeneration == s.Generation
+}
+
+const approvedRolloutAnnotation = "eno.azure.io/approved-rollout"
+
+// ApprovedRolloutWaves returns the number of waves of the current generation's rollout that have been approved.
+// math.MaxInt is returned when the entire rollout has been approved.
+func (s *Synthesizer) ApprovedRolloutWaves() int {
+	val, ok := s.GetAnnotations()[approvedRolloutAnnotation]
+	if !ok {
+		return 0
+	}
+
+	genStr, countStr, hasCount := strings.Cut(val, "/")
+	gen, err := strconv.ParseInt(genStr, 10, 64)
+	if err != nil || gen != s.Generation {
+		return 0 // approvals don't carry over to later generations
+	}
+	if !hasCount {
+		return math.MaxInt
+	}
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count < 0 {
+		return 0
+	}
+	return count
 }
 
 type SynthesizerRef struct {
 	Name string `json:"name,omitempty"`
+
+	// Revision pins the composition to a previous generation of the synthesizer.
+	// The composition will be synthesized using the spec captured by the corresponding SynthesizerRevision
+	// until the pin is removed, regardless of any later changes to the synthesizer.
+	//
+	// This is useful for rolling back particular compositions during incidents without waiting for a full rollout.
+	//
+	// +kubebuilder:validation:Minimum:=1
+	Revision *int64 `json:"revision,omitempty"`
 }
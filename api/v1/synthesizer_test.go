@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+)
+
+func TestApprovedRolloutWaves(t *testing.T) {
+	tests := []struct {
+		Value  *string
+		Expect int
+	}{
+		{Value: nil, Expect: 0},
+		{Value: ptr.To("3"), Expect: math.MaxInt},
+		{Value: ptr.To("3/2"), Expect: 2},
+		{Value: ptr.To("3/0"), Expect: 0},
+		{Value: ptr.To("2"), Expect: 0},
+		{Value: ptr.To("2/5"), Expect: 0},
+		{Value: ptr.To("3/-1"), Expect: 0},
+		{Value: ptr.To("nope"), Expect: 0},
+	}
+	for _, tc := range tests {
+		synth := &Synthesizer{}
+		synth.Generation = 3
+		if tc.Value != nil {
+			synth.Annotations = map[string]string{"eno.azure.io/approved-rollout": *tc.Value}
+		}
+		assert.Equal(t, tc.Expect, synth.ApprovedRolloutWaves(), tc.Value)
+	}
+
+	comp := &Composition{}
+	synth := &Synthesizer{}
+	synth.Generation = 3
+	assert.False(t, comp.RolloutApproved(synth))
+	comp.Annotations = map[string]string{"eno.azure.io/approved-synthesizer-generation": "3"}
+	assert.True(t, comp.RolloutApproved(synth))
+	synth.Generation++
+	assert.False(t, comp.RolloutApproved(synth))
+}
+
+func TestResourceAllowlist(t *testing.T) {
+	deployment := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	configMap := schema.GroupKind{Kind: "ConfigMap"}
+	namespace := schema.GroupKind{Kind: "Namespace"}
+	clusterRole := schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}
+
+	var nilList *ResourceAllowlist
+	assert.True(t, nilList.Allows(clusterRole, "", "admin"))
+
+	kinds := &ResourceAllowlist{Kinds: []AllowedKind{{Group: "apps", Kind: "*"}, {Kind: "ConfigMap"}}}
+	assert.True(t, kinds.Allows(deployment, "any", "foo"))
+	assert.True(t, kinds.Allows(configMap, "any", "foo"))
+	assert.False(t, kinds.Allows(schema.GroupKind{Kind: "Secret"}, "any", "foo"))
+	assert.False(t, kinds.Allows(schema.GroupKind{Group: "other", Kind: "ConfigMap"}, "any", "foo"))
+	assert.False(t, kinds.Allows(clusterRole, "", "admin"))
+
+	namespaces := &ResourceAllowlist{Namespaces: []string{"tenant-a"}}
+	assert.True(t, namespaces.Allows(deployment, "tenant-a", "foo"))
+	assert.False(t, namespaces.Allows(deployment, "tenant-b", "foo"))
+	assert.True(t, namespaces.Allows(namespace, "", "tenant-a"))
+	assert.False(t, namespaces.Allows(namespace, "", "tenant-b"))
+	assert.False(t, namespaces.Allows(clusterRole, "", "admin"))
+
+	both := &ResourceAllowlist{Namespaces: []string{"tenant-a"}, Kinds: []AllowedKind{{Kind: "ConfigMap"}}}
+	assert.True(t, both.Allows(configMap, "tenant-a", "foo"))
+	assert.False(t, both.Allows(configMap, "tenant-b", "foo"))
+	assert.False(t, both.Allows(deployment, "tenant-a", "foo"))
+	assert.False(t, both.Allows(namespace, "", "tenant-a"), "namespaces must also be allowed by kinds")
+}
@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+type SynthesizerRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SynthesizerRevision `json:"items"`
+}
+
+// SynthesizerRevisions are immutable snapshots of a synthesizer's spec at a particular generation.
+//
+// Eno captures a revision every time it observes a new synthesizer generation.
+// Compositions can be pinned to a revision using their synthesizer ref, which causes them to be
+// synthesized using the captured spec instead of the synthesizer's current spec.
+//
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Synthesizer",type=string,JSONPath=`.spec.synthesizer`
+// +kubebuilder:printcolumn:name="Generation",type=integer,JSONPath=`.spec.generation`
+// +kubebuilder:printcolumn:name="Image",type=string,JSONPath=`.spec.synthesizerSpec.image`
+type SynthesizerRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SynthesizerRevisionSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="synthesizer revisions are immutable"
+type SynthesizerRevisionSpec struct {
+	// Name of the synthesizer this revision was captured from.
+	Synthesizer string `json:"synthesizer"`
+
+	// The synthesizer's generation at the time this revision was captured.
+	Generation int64 `json:"generation"`
+
+	// The synthesizer's spec at the time this revision was captured.
+	SynthesizerSpec SynthesizerSpec `json:"synthesizerSpec"`
+}
+
+// SynthesizerRevisionName returns the name of the revision that captures a particular generation of the named synthesizer.
+func SynthesizerRevisionName(synth string, generation int64) string {
+	return fmt.Sprintf("%s-%d", synth, generation)
+}
+
+// Synthesizer returns a copy of the synthesizer as it existed when this revision was captured.
+func (r *SynthesizerRevision) Synthesizer() *Synthesizer {
+	synth := &Synthesizer{}
+	synth.Name = r.Spec.Synthesizer
+	synth.Generation = r.Spec.Generation
+	synth.CreationTimestamp = r.CreationTimestamp
+	synth.Spec = *r.Spec.SynthesizerSpec.DeepCopy()
+	return synth
+}
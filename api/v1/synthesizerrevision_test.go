@@ -0,0 +1,24 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevisionSynthesizer(t *testing.T) {
+	rev := &SynthesizerRevision{}
+	rev.Name = SynthesizerRevisionName("test-synth", 3)
+	rev.Spec.Synthesizer = "test-synth"
+	rev.Spec.Generation = 3
+	rev.Spec.SynthesizerSpec.Command = []string{"foo"}
+
+	synth := rev.Synthesizer()
+	assert.Equal(t, "test-synth-3", rev.Name)
+	assert.Equal(t, "test-synth", synth.Name)
+	assert.Equal(t, int64(3), synth.Generation)
+	assert.Equal(t, []string{"foo"}, synth.Spec.Command)
+
+	synth.Spec.Command[0] = "bar"
+	assert.Equal(t, []string{"foo"}, rev.Spec.SynthesizerSpec.Command, "deep copied")
+}
@@ -20,7 +20,9 @@ var (
 
 func init() {
 	SchemeBuilder.Register(&SynthesizerList{}, &Synthesizer{})
+	SchemeBuilder.Register(&SynthesizerRevisionList{}, &SynthesizerRevision{})
 	SchemeBuilder.Register(&CompositionList{}, &Composition{})
 	SchemeBuilder.Register(&SymphonyList{}, &Symphony{})
+	SchemeBuilder.Register(&PolicyList{}, &Policy{})
 	SchemeBuilder.Register(&ResourceSliceList{}, &ResourceSlice{})
 }
@@ -10,6 +10,21 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowedKind) DeepCopyInto(out *AllowedKind) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedKind.
+func (in *AllowedKind) DeepCopy() *AllowedKind {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowedKind)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Binding) DeepCopyInto(out *Binding) {
 	*out = *in
@@ -85,10 +100,25 @@ func (in *CompositionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositionRef) DeepCopyInto(out *CompositionRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionRef.
+func (in *CompositionRef) DeepCopy() *CompositionRef {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositionRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompositionSpec) DeepCopyInto(out *CompositionSpec) {
 	*out = *in
-	out.Synthesizer = in.Synthesizer
+	in.Synthesizer.DeepCopyInto(&out.Synthesizer)
 	if in.Bindings != nil {
 		in, out := &in.Bindings, &out.Bindings
 		*out = make([]Binding, len(*in))
@@ -97,7 +127,14 @@ func (in *CompositionSpec) DeepCopyInto(out *CompositionSpec) {
 	if in.SynthesisEnv != nil {
 		in, out := &in.SynthesisEnv, &out.SynthesisEnv
 		*out = make([]EnvVar, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -155,6 +192,11 @@ func (in *CompositionStatus) DeepCopy() *CompositionStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvVar) DeepCopyInto(out *EnvVar) {
 	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(EnvVarSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvVar.
@@ -167,6 +209,31 @@ func (in *EnvVar) DeepCopy() *EnvVar {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvVarSource) DeepCopyInto(out *EnvVarSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvVarSource.
+func (in *EnvVarSource) DeepCopy() *EnvVarSource {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvVarSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Input) DeepCopyInto(out *Input) {
 	*out = *in
@@ -262,6 +329,49 @@ func (in *PodOverrides) DeepCopyInto(out *PodOverrides) {
 		*out = new(corev1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RuntimeClassName != nil {
+		in, out := &in.RuntimeClassName, &out.RuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodOverrides.
@@ -274,6 +384,99 @@ func (in *PodOverrides) DeepCopy() *PodOverrides {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Policy) DeepCopyInto(out *Policy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Policy.
+func (in *Policy) DeepCopy() *Policy {
+	if in == nil {
+		return nil
+	}
+	out := new(Policy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Policy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyList) DeepCopyInto(out *PolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Policy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyList.
+func (in *PolicyList) DeepCopy() *PolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRule) DeepCopyInto(out *PolicyRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRule.
+func (in *PolicyRule) DeepCopy() *PolicyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicySpec) DeepCopyInto(out *PolicySpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]PolicyRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicySpec.
+func (in *PolicySpec) DeepCopy() *PolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Ref) DeepCopyInto(out *Ref) {
 	*out = *in
@@ -290,6 +493,31 @@ func (in *Ref) DeepCopy() *Ref {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceAllowlist) DeepCopyInto(out *ResourceAllowlist) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]AllowedKind, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceAllowlist.
+func (in *ResourceAllowlist) DeepCopy() *ResourceAllowlist {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceAllowlist)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceBinding) DeepCopyInto(out *ResourceBinding) {
 	*out = *in
@@ -402,6 +630,16 @@ func (in *ResourceSliceSpec) DeepCopyInto(out *ResourceSliceSpec) {
 		*out = make([]Manifest, len(*in))
 		copy(*out, *in)
 	}
+	if in.Allowlist != nil {
+		in, out := &in.Allowlist, &out.Allowlist
+		*out = new(ResourceAllowlist)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServerSideApply != nil {
+		in, out := &in.ServerSideApply, &out.ServerSideApply
+		*out = new(ServerSideApply)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSliceSpec.
@@ -443,6 +681,16 @@ func (in *ResourceState) DeepCopyInto(out *ResourceState) {
 		in, out := &in.Ready, &out.Ready
 		*out = (*in).DeepCopy()
 	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DriftedFields != nil {
+		in, out := &in.DriftedFields, &out.DriftedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceState.
@@ -477,6 +725,112 @@ func (in *Result) DeepCopy() *Result {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.Halted != nil {
+		in, out := &in.Halted, &out.Halted
+		*out = (*in).DeepCopy()
+	}
+	if in.AwaitingApproval != nil {
+		in, out := &in.AwaitingApproval, &out.AwaitingApproval
+		*out = make([]CompositionRef, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	if in.Waves != nil {
+		in, out := &in.Waves, &out.Waves
+		*out = make([]RolloutWave, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.ReadyTimeout != nil {
+		in, out := &in.ReadyTimeout, &out.ReadyTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]TimeWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Blackouts != nil {
+		in, out := &in.Blackouts, &out.Blackouts
+		*out = make([]TimeWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutWave) DeepCopyInto(out *RolloutWave) {
+	*out = *in
+	if in.BakeTime != nil {
+		in, out := &in.BakeTime, &out.BakeTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWave.
+func (in *RolloutWave) DeepCopy() *RolloutWave {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutWave)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerSideApply) DeepCopyInto(out *ServerSideApply) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerSideApply.
+func (in *ServerSideApply) DeepCopy() *ServerSideApply {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerSideApply)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SimplifiedStatus) DeepCopyInto(out *SimplifiedStatus) {
 	*out = *in
@@ -569,7 +923,14 @@ func (in *SymphonySpec) DeepCopyInto(out *SymphonySpec) {
 	if in.SynthesisEnv != nil {
 		in, out := &in.SynthesisEnv, &out.SynthesisEnv
 		*out = make([]EnvVar, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -601,7 +962,9 @@ func (in *SymphonyStatus) DeepCopyInto(out *SymphonyStatus) {
 	if in.Synthesizers != nil {
 		in, out := &in.Synthesizers, &out.Synthesizers
 		*out = make([]SynthesizerRef, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -663,6 +1026,18 @@ func (in *Synthesis) DeepCopyInto(out *Synthesis) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EnvRevisions != nil {
+		in, out := &in.EnvRevisions, &out.EnvRevisions
+		*out = make([]InputRevisions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]SynthesisStep, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Synthesis.
@@ -675,13 +1050,29 @@ func (in *Synthesis) DeepCopy() *Synthesis {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SynthesisStep) DeepCopyInto(out *SynthesisStep) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynthesisStep.
+func (in *SynthesisStep) DeepCopy() *SynthesisStep {
+	if in == nil {
+		return nil
+	}
+	out := new(SynthesisStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Synthesizer) DeepCopyInto(out *Synthesizer) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Synthesizer.
@@ -702,6 +1093,26 @@ func (in *Synthesizer) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SynthesizerFunction) DeepCopyInto(out *SynthesizerFunction) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynthesizerFunction.
+func (in *SynthesizerFunction) DeepCopy() *SynthesizerFunction {
+	if in == nil {
+		return nil
+	}
+	out := new(SynthesizerFunction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SynthesizerList) DeepCopyInto(out *SynthesizerList) {
 	*out = *in
@@ -737,6 +1148,11 @@ func (in *SynthesizerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SynthesizerRef) DeepCopyInto(out *SynthesizerRef) {
 	*out = *in
+	if in.Revision != nil {
+		in, out := &in.Revision, &out.Revision
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynthesizerRef.
@@ -749,6 +1165,95 @@ func (in *SynthesizerRef) DeepCopy() *SynthesizerRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SynthesizerRevision) DeepCopyInto(out *SynthesizerRevision) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynthesizerRevision.
+func (in *SynthesizerRevision) DeepCopy() *SynthesizerRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(SynthesizerRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SynthesizerRevision) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SynthesizerRevisionList) DeepCopyInto(out *SynthesizerRevisionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SynthesizerRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynthesizerRevisionList.
+func (in *SynthesizerRevisionList) DeepCopy() *SynthesizerRevisionList {
+	if in == nil {
+		return nil
+	}
+	out := new(SynthesizerRevisionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SynthesizerRevisionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SynthesizerRevisionSpec) DeepCopyInto(out *SynthesizerRevisionSpec) {
+	*out = *in
+	in.SynthesizerSpec.DeepCopyInto(&out.SynthesizerSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynthesizerRevisionSpec.
+func (in *SynthesizerRevisionSpec) DeepCopy() *SynthesizerRevisionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SynthesizerRevisionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SynthesizerService) DeepCopyInto(out *SynthesizerService) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynthesizerService.
+func (in *SynthesizerService) DeepCopy() *SynthesizerService {
+	if in == nil {
+		return nil
+	}
+	out := new(SynthesizerService)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SynthesizerSpec) DeepCopyInto(out *SynthesizerSpec) {
 	*out = *in
@@ -757,6 +1262,13 @@ func (in *SynthesizerSpec) DeepCopyInto(out *SynthesizerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Functions != nil {
+		in, out := &in.Functions, &out.Functions
+		*out = make([]SynthesizerFunction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ExecTimeout != nil {
 		in, out := &in.ExecTimeout, &out.ExecTimeout
 		*out = new(metav1.Duration)
@@ -778,6 +1290,36 @@ func (in *SynthesizerSpec) DeepCopyInto(out *SynthesizerSpec) {
 		copy(*out, *in)
 	}
 	in.PodOverrides.DeepCopyInto(&out.PodOverrides)
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConcurrencyLimit != nil {
+		in, out := &in.ConcurrencyLimit, &out.ConcurrencyLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxAttempts != nil {
+		in, out := &in.MaxAttempts, &out.MaxAttempts
+		*out = new(int)
+		**out = **in
+	}
+	if in.Allowlist != nil {
+		in, out := &in.Allowlist, &out.Allowlist
+		*out = new(ResourceAllowlist)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServerSideApply != nil {
+		in, out := &in.ServerSideApply, &out.ServerSideApply
+		*out = new(ServerSideApply)
+		**out = **in
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(SynthesizerService)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynthesizerSpec.
@@ -793,6 +1335,11 @@ func (in *SynthesizerSpec) DeepCopy() *SynthesizerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SynthesizerStatus) DeepCopyInto(out *SynthesizerStatus) {
 	*out = *in
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynthesizerStatus.
@@ -805,6 +1352,34 @@ func (in *SynthesizerStatus) DeepCopy() *SynthesizerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeWindow) DeepCopyInto(out *TimeWindow) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeWindow.
+func (in *TimeWindow) DeepCopy() *TimeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Variation) DeepCopyInto(out *Variation) {
 	*out = *in
@@ -822,12 +1397,17 @@ func (in *Variation) DeepCopyInto(out *Variation) {
 			(*out)[key] = val
 		}
 	}
-	out.Synthesizer = in.Synthesizer
+	in.Synthesizer.DeepCopyInto(&out.Synthesizer)
 	if in.Bindings != nil {
 		in, out := &in.Bindings, &out.Bindings
 		*out = make([]Binding, len(*in))
 		copy(*out, *in)
 	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Variation.
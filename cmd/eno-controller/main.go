@@ -20,11 +20,13 @@ import (
 	v1 "github.com/Azure/eno/api/v1"
 	"github.com/Azure/eno/internal/controllers/aggregation"
 	"github.com/Azure/eno/internal/controllers/replication"
+	"github.com/Azure/eno/internal/controllers/revision"
 	"github.com/Azure/eno/internal/controllers/scheduling"
 	"github.com/Azure/eno/internal/controllers/selfhealing"
 	"github.com/Azure/eno/internal/controllers/synthesis"
 	"github.com/Azure/eno/internal/controllers/watch"
 	"github.com/Azure/eno/internal/controllers/watchdog"
+	"github.com/Azure/eno/internal/discovery"
 	"github.com/Azure/eno/internal/execution"
 	"github.com/Azure/eno/internal/manager"
 )
@@ -50,12 +52,13 @@ func runController() error {
 	var (
 		debugLogging           bool
 		watchdogThres          time.Duration
-		rolloutCooldown        time.Duration
 		selfHealingGracePeriod time.Duration
 		taintToleration        string
 		nodeAffinity           string
-		concurrencyLimit       int
+		revisionHistoryLimit   int
+		watchEnvRefs           bool
 		synconf                = &synthesis.Config{}
+		schedconf              = &scheduling.Config{}
 
 		mgrOpts = &manager.Options{
 			Rest: ctrl.GetConfigOrDie(),
@@ -64,13 +67,34 @@ func runController() error {
 	flag.StringVar(&synconf.PodNamespace, "synthesizer-pod-namespace", os.Getenv("POD_NAMESPACE"), "Namespace to create synthesizer pods in. Defaults to POD_NAMESPACE.")
 	flag.StringVar(&synconf.ExecutorImage, "executor-image", os.Getenv("EXECUTOR_IMAGE"), "Reference to the image that will be used to execute synthesizers. Defaults to EXECUTOR_IMAGE.")
 	flag.StringVar(&synconf.PodServiceAccount, "synthesizer-pod-service-account", "", "Service account name to be assigned to synthesizer Pods.")
+	flag.BoolVar(&watchEnvRefs, "watch-synthesis-env-refs", false, "Resynthesize compositions when Secrets or ConfigMaps referenced by their synthesisEnv (with resynthesize=true) change. Requires list/watch access to Secrets and ConfigMaps in --synthesizer-pod-namespace.")
+	flag.IntVar(&synconf.ServiceConcurrency, "service-synthesis-concurrency", 10, "Max number of syntheses that will call service-backed synthesizers at the same time.")
 	flag.DurationVar(&synconf.ContainerCreationTimeout, "container-creation-ttl", time.Second*3, "Timeout when waiting for kubelet to ack scheduled pods. Protects tail latency from kubelet network partitions")
 	flag.BoolVar(&debugLogging, "debug", true, "Enable debug logging")
 	flag.DurationVar(&watchdogThres, "watchdog-threshold", time.Minute*3, "How long before the watchdog considers a mid-transition resource to be stuck")
-	flag.DurationVar(&rolloutCooldown, "rollout-cooldown", time.Minute, "How long before an update to a related resource (synthesizer, bindings, etc.) will trigger a second composition's re-synthesis")
+	flag.DurationVar(&schedconf.CooldownPeriod, "rollout-cooldown", time.Minute, "How long before an update to a related resource (synthesizer, bindings, etc.) will trigger a second composition's re-synthesis")
 	flag.StringVar(&taintToleration, "taint-toleration", "", "Node NoSchedule taint to be tolerated by synthesizer pods e.g. taintKey=taintValue to match on value, just taintKey to match on presence of the taint")
 	flag.StringVar(&nodeAffinity, "node-affinity", "", "Synthesizer pods will be created with this required node affinity expression e.g. labelKey=labelValue to match on value, just labelKey to match on presence of the label")
-	flag.IntVar(&concurrencyLimit, "concurrency-limit", 10, "Upper bound on active syntheses. This effectively limits the number of running synthesizer pods spawned by Eno.")
+	flag.IntVar(&schedconf.ConcurrencyLimit, "concurrency-limit", 10, "Upper bound on active syntheses. This effectively limits the number of running synthesizer pods spawned by Eno.")
+	flag.IntVar(&schedconf.ReservedConcurrency, "reserved-concurrency", 0, "Number of --concurrency-limit slots that can only be used to synthesize compositions with a positive spec.priority.")
+	flag.IntVar(&schedconf.NamespaceConcurrencyLimit, "namespace-concurrency-limit", 0, "Upper bound on active syntheses of compositions in any one namespace. Zero disables the limit.")
+	flag.Func("rollout-window", "Window during which deferred syntheses can be dispatched: a cron expression (UTC) followed by a duration e.g. \"0 22 * * 5 60h\", or two RFC3339 timestamps separated by a slash. Can be given multiple times.", func(s string) error {
+		w, err := scheduling.ParseTimeWindow(s)
+		if err != nil {
+			return err
+		}
+		schedconf.Windows = append(schedconf.Windows, w)
+		return nil
+	})
+	flag.Func("rollout-blackout", "Period during which deferred syntheses will not be dispatched, in the same format as --rollout-window. Can be given multiple times.", func(s string) error {
+		w, err := scheduling.ParseTimeWindow(s)
+		if err != nil {
+			return err
+		}
+		schedconf.Blackouts = append(schedconf.Blackouts, w)
+		return nil
+	})
+	flag.IntVar(&revisionHistoryLimit, "synthesizer-revision-history-limit", 10, "How many revisions of each synthesizer to retain. Revisions that compositions are pinned to are always retained.")
 	flag.DurationVar(&selfHealingGracePeriod, "self-healing-grace-period", time.Minute*5, "How long before the self-healing controllers are allowed to start the resynthesis process.")
 	mgrOpts.Bind(flag.CommandLine)
 	flag.Parse()
@@ -112,6 +136,11 @@ func runController() error {
 		return fmt.Errorf("constructing pod lifecycle controller: %w", err)
 	}
 
+	err = synthesis.NewServiceSynthesisController(mgr, synconf)
+	if err != nil {
+		return fmt.Errorf("constructing service synthesis controller: %w", err)
+	}
+
 	err = synthesis.NewSliceCleanupController(mgr)
 	if err != nil {
 		return fmt.Errorf("constructing resource slice cleanup controller: %w", err)
@@ -147,7 +176,20 @@ func runController() error {
 		return fmt.Errorf("constructing watch controller: %w", err)
 	}
 
-	err = scheduling.NewController(mgr, concurrencyLimit, rolloutCooldown)
+	if watchEnvRefs {
+		err = watch.NewEnvController(mgr, synconf.PodNamespace)
+		if err != nil {
+			return fmt.Errorf("constructing synthesis env watch controller: %w", err)
+		}
+	}
+
+	err = revision.NewController(mgr, revisionHistoryLimit)
+	if err != nil {
+		return fmt.Errorf("constructing synthesizer revision controller: %w", err)
+	}
+
+	schedconf.DebugHandler = true
+	err = scheduling.NewController(mgr, schedconf)
 	if err != nil {
 		return fmt.Errorf("constructing synthesis scheduling controller: %w", err)
 	}
@@ -219,10 +261,18 @@ func runExecutor() {
 		os.Exit(1)
 	}
 
+	disc, err := discovery.NewCache(rc, 2)
+	if err != nil {
+		logger.Error(err, "building discovery cache")
+		os.Exit(1)
+	}
+
 	e := &execution.Executor{
-		Reader:  client,
-		Writer:  client,
-		Handler: execution.NewExecHandler(),
+		Reader:      client,
+		Writer:      client,
+		Handler:     execution.NewExecHandler(),
+		PipelineDir: "/eno/pipeline", // shared by every container of the pod
+		Schemas:     disc,
 	}
 	err = e.Synthesize(ctx, execution.LoadEnv())
 	if err != nil {
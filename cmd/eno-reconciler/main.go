@@ -55,7 +55,8 @@ func run() error {
 	flag.StringVar(&compositionSelector, "composition-label-selector", labels.Everything().String(), "Optional label selector for compositions to be reconciled")
 	flag.StringVar(&compositionNamespace, "composition-namespace", metav1.NamespaceAll, "Optional namespace to limit compositions that will be reconciled")
 	flag.DurationVar(&namespaceCreationGracePeriod, "ns-creation-grace-period", time.Second, "A namespace is assumed to be missing if it doesn't exist once one of its resources has existed for this long")
-	flag.BoolVar(&namespaceCleanup, "namespace-cleanup", true, "Clean up orphaned resources caused by namespace force-deletions")
+	flag.BoolVar(&namespaceCleanup, "namespace-cleanup", true, "Clean up orphaned resources caused by namespace force-deletions. Disabled in dry-run mode")
+	flag.BoolVar(&recOpts.DryRun, "dry-run", false, "Send mutating requests to the remote apiserver as dry-run requests, and record the action that would have been taken for each resource instead of marking it as reconciled. Resources already reconciled by another reconciler are left as-is, and actions aren't counted by the eno_reconciliation_actions_total metric")
 	mgrOpts.Bind(flag.CommandLine)
 	flag.Parse()
 
@@ -86,7 +87,7 @@ func run() error {
 		return fmt.Errorf("constructing manager: %w", err)
 	}
 
-	if namespaceCleanup {
+	if namespaceCleanup && !recOpts.DryRun {
 		err = liveness.NewNamespaceController(mgr, 5, namespaceCreationGracePeriod)
 		if err != nil {
 			return fmt.Errorf("constructing namespace liveness controller: %w", err)
@@ -0,0 +1,80 @@
+package reconciliation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/Azure/eno/internal/testutil"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+)
+
+// TestAllowlist proves that the allowlist in effect when a synthesis was written applies to its resources,
+// so tightening the allowlist doesn't block updates or deletes of resources that were already applied.
+func TestAllowlist(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	mgr := testutil.NewManager(t)
+	upstream := mgr.GetClient()
+	downstream := mgr.DownstreamClient
+
+	registerControllers(t, mgr)
+	testutil.WithFakeExecutor(t, mgr, func(ctx context.Context, s *apiv1.Synthesizer, input *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+		return &krmv1.ResourceList{Items: []*unstructured.Unstructured{
+			newTestConfigMap("test-obj", "bar", map[string]string{"eno.azure.io/reconcile-interval": "10ms"}),
+		}}, nil
+	})
+
+	// Test subject
+	setupTestSubject(t, mgr)
+	mgr.Start(t)
+
+	// Changes to the synthesizer require approval, so the existing synthesis stays current
+	syn := &apiv1.Synthesizer{}
+	syn.Name = "test-syn"
+	syn.Spec.Image = "create"
+	syn.Spec.Rollout = &apiv1.RolloutStrategy{RequireApproval: true}
+	require.NoError(t, upstream.Create(ctx, syn))
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Spec.Synthesizer.Name = syn.Name
+	require.NoError(t, upstream.Create(ctx, comp))
+
+	// Wait for resource to be created
+	obj := &corev1.ConfigMap{}
+	obj.SetName("test-obj")
+	obj.SetNamespace("default")
+	testutil.Eventually(t, func() bool {
+		return downstream.Get(ctx, client.ObjectKeyFromObject(obj), obj) == nil
+	})
+
+	// Only allow resources in another namespace
+	err := retry.RetryOnConflict(testutil.Backoff, func() error {
+		upstream.Get(ctx, client.ObjectKeyFromObject(syn), syn)
+		syn.Spec.Allowlist = &apiv1.ResourceAllowlist{Namespaces: []string{"other"}}
+		return upstream.Update(ctx, syn)
+	})
+	require.NoError(t, err)
+
+	// Drift is still corrected since the current synthesis predates the change
+	obj.Data["foo"] = "baz"
+	require.NoError(t, downstream.Update(ctx, obj))
+
+	testutil.Eventually(t, func() bool {
+		return downstream.Get(ctx, client.ObjectKeyFromObject(obj), obj) == nil && obj.Data["foo"] == "bar"
+	})
+
+	// Deletes aren't blocked either
+	require.NoError(t, upstream.Delete(ctx, comp))
+	testutil.Eventually(t, func() bool {
+		return errors.IsNotFound(downstream.Get(ctx, client.ObjectKeyFromObject(obj), obj))
+	})
+}
@@ -3,11 +3,16 @@ package reconciliation
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -27,6 +32,14 @@ import (
 
 var insecureLogPatch = os.Getenv("INSECURE_LOG_PATCH") == "true"
 
+const (
+	// fieldManager is the field manager used when reconciling resources using server-side apply.
+	fieldManager = "eno"
+
+	// maxReportedFields bounds the number of fields (server-side apply conflicts, drift) written to a resource's status.
+	maxReportedFields = 10
+)
+
 type Options struct {
 	Manager     ctrl.Manager
 	Cache       *reconstitution.Cache
@@ -37,6 +50,10 @@ type Options struct {
 
 	Timeout               time.Duration
 	ReadinessPollInterval time.Duration
+
+	// DryRun sends every mutating request to the downstream apiserver as a dry-run request.
+	// The action that would have been taken is recorded in the resource's status instead of marking it as reconciled.
+	DryRun bool
 }
 
 type Controller struct {
@@ -47,6 +64,7 @@ type Controller struct {
 	readinessPollInterval time.Duration
 	upstreamClient        client.Client
 	discovery             *discovery.Cache
+	dryRun                bool
 }
 
 func New(opts Options) (*Controller, error) {
@@ -56,6 +74,9 @@ func New(opts Options) (*Controller, error) {
 	if err != nil {
 		return nil, err
 	}
+	if opts.DryRun {
+		upstreamClient = client.NewDryRunClient(upstreamClient)
+	}
 
 	disc, err := discovery.NewCache(opts.Downstream, opts.DiscoveryRPS)
 	if err != nil {
@@ -70,6 +91,7 @@ func New(opts Options) (*Controller, error) {
 		readinessPollInterval: opts.ReadinessPollInterval,
 		upstreamClient:        upstreamClient,
 		discovery:             disc,
+		dryRun:                opts.DryRun,
 	}, nil
 }
 
@@ -111,6 +133,14 @@ func (c *Controller) Reconcile(ctx context.Context, req *reconstitution.Request)
 	logger = logger.WithValues("resourceKind", resource.Ref.Kind, "resourceName", resource.Ref.Name, "resourceNamespace", resource.Ref.Namespace)
 	ctx = logr.NewContext(ctx, logger)
 
+	// Resources that weren't allowed by the synthesizer when their slice was written are never created or updated.
+	// Deletes are allowed since they can only remove resources that were previously applied.
+	if resource.Denied && !resource.Deleted() {
+		logger.V(0).Info("resource is not allowed by the synthesizer's allowlist - skipping")
+		c.writeBuffer.PatchStatusAsync(ctx, &resource.ManifestRef, patchDeniedResourceState())
+		return ctrl.Result{}, nil
+	}
+
 	// Keep track of the last reconciliation time and report on it relative to the resource's reconcile interval
 	// This is useful for identifying cases where the loop can't keep up
 	if resource.ReconcileInterval != nil {
@@ -190,20 +220,56 @@ func (c *Controller) Reconcile(ctx context.Context, req *reconstitution.Request)
 		}
 	}
 
-	modified, err := c.reconcileResource(ctx, comp, prev, resource, current)
-	if err != nil {
-		return ctrl.Result{}, err
-	}
-	// If we modified the resource, we should also re-evaluate readiness
-	// without waiting for the interval.
-	if modified {
-		return ctrl.Result{Requeue: true}, nil
+	// Resources in observe-only mode are never updated - drift is reported instead
+	var action string
+	var drifted bool
+	var driftedFields []string
+	if (resource.ObserveOnly || comp.Annotations["eno.azure.io/observe-only"] == "true") && current != nil && !resource.Deleted() {
+		drifted, driftedFields, err = c.detectDrift(ctx, prev, resource, current)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if drifted && (status == nil || !status.Drifted) {
+			driftDetections.Inc() // only count resources that weren't already known to have drifted
+		}
+	} else {
+		action, err = c.reconcileResource(ctx, comp, prev, resource, current)
+		if conflicts := applyConflicts(err); conflicts != nil {
+			logger.V(0).Info("resource has server-side apply conflicts - skipping", "conflicts", conflicts)
+			c.writeBuffer.PatchStatusAsync(ctx, &resource.ManifestRef, patchConflictedResourceState(conflicts))
+			if resource.ReconcileInterval != nil && resource.ReconcileInterval.Duration > 0 {
+				return ctrl.Result{RequeueAfter: wait.Jitter(resource.ReconcileInterval.Duration, 0.1)}, nil
+			}
+			return ctrl.Result{RequeueAfter: wait.Jitter(c.readinessPollInterval, 0.1)}, nil
+		}
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		// Resources being recreated are no longer reconciled or ready.
+		// So resources in later readiness groups that haven't been reconciled yet wait for the new version to become ready.
+		if action == "recreate" && !c.dryRun {
+			c.writeBuffer.PatchStatusAsync(ctx, &resource.ManifestRef, patchRecreatingResourceState())
+
+			// Foreground deletion can take a while, so poll for it to complete instead of backing off
+			if current.GetDeletionTimestamp() != nil {
+				return ctrl.Result{RequeueAfter: wait.Jitter(c.readinessPollInterval, 0.1)}, nil
+			}
+		}
+		// If we modified the resource, we should also re-evaluate readiness
+		// without waiting for the interval.
+		if action != "" && !c.dryRun {
+			return ctrl.Result{Requeue: true}, nil
+		}
 	}
 
-	deleted := current == nil ||
-		current.GetDeletionTimestamp() != nil ||
-		(resource.Deleted() && comp.Annotations["eno.azure.io/deletion-strategy"] == "orphan") // orphaning should be reflected on the status.
-	c.writeBuffer.PatchStatusAsync(ctx, &resource.ManifestRef, patchResourceState(deleted, ready))
+	if c.dryRun {
+		c.writeBuffer.PatchStatusAsync(ctx, &resource.ManifestRef, patchDryRunResourceState(ready, action))
+	} else {
+		deleted := current == nil ||
+			current.GetDeletionTimestamp() != nil ||
+			(resource.Deleted() && comp.Annotations["eno.azure.io/deletion-strategy"] == "orphan") // orphaning should be reflected on the status.
+		c.writeBuffer.PatchStatusAsync(ctx, &resource.ManifestRef, patchResourceState(deleted, ready, drifted, driftedFields))
+	}
 	if ready == nil {
 		return ctrl.Result{RequeueAfter: wait.Jitter(c.readinessPollInterval, 0.1)}, nil
 	}
@@ -213,7 +279,7 @@ func (c *Controller) Reconcile(ctx context.Context, req *reconstitution.Request)
 	return ctrl.Result{}, nil
 }
 
-func (c *Controller) reconcileResource(ctx context.Context, comp *apiv1.Composition, prev, resource *reconstitution.Resource, current *unstructured.Unstructured) (bool, error) {
+func (c *Controller) reconcileResource(ctx context.Context, comp *apiv1.Composition, prev, resource *reconstitution.Resource, current *unstructured.Unstructured) (string, error) {
 	logger := logr.FromContextOrDiscard(ctx)
 	start := time.Now()
 	defer func() {
@@ -222,73 +288,90 @@ func (c *Controller) reconcileResource(ctx context.Context, comp *apiv1.Composit
 
 	if resource.Deleted() {
 		if current == nil || current.GetDeletionTimestamp() != nil {
-			return false, nil // already deleted - nothing to do
+			return "", nil // already deleted - nothing to do
 		}
 		if comp.Annotations["eno.azure.io/deletion-strategy"] == "orphan" {
-			return false, nil
+			return "", nil
 		}
 
-		reconciliationActions.WithLabelValues("delete").Inc()
+		c.recordAction("delete")
 		err := c.upstreamClient.Delete(ctx, current)
 		if err != nil {
-			return true, client.IgnoreNotFound(fmt.Errorf("deleting resource: %w", err))
+			return "delete", client.IgnoreNotFound(fmt.Errorf("deleting resource: %w", err))
 		}
 		logger.V(0).Info("deleted resource")
-		return true, nil
+		return "delete", nil
 	}
 
 	if resource.Patch != nil && current == nil {
 		logger.V(1).Info("resource doesn't exist - skipping patch")
-		return false, nil
+		return "", nil
+	}
+
+	// Resources being recreated are created again once the deletion completes
+	if resource.Recreate && resource.Patch == nil && current != nil && current.GetDeletionTimestamp() != nil {
+		logger.V(1).Info("waiting for resource to be deleted before recreating it")
+		return "recreate", nil
+	}
+
+	if ssa, force := serverSideApply(resource); ssa && resource.Patch == nil {
+		if current != nil && resource.DisableUpdates {
+			return "", nil
+		}
+		action, err := c.apply(ctx, resource, current, force)
+		if current != nil && resource.Recreate && isImmutableFieldErr(err) {
+			return c.recreate(ctx, current, err)
+		}
+		return action, err
 	}
 
 	// Create the resource when it doesn't exist
 	if current == nil {
-		reconciliationActions.WithLabelValues("create").Inc()
+		c.recordAction("create")
 		obj, err := resource.Parse()
 		if err != nil {
-			return false, fmt.Errorf("invalid resource: %w", err)
+			return "", fmt.Errorf("invalid resource: %w", err)
 		}
 		err = c.upstreamClient.Create(ctx, obj)
 		if err != nil {
-			return false, fmt.Errorf("creating resource: %w", err)
+			return "", fmt.Errorf("creating resource: %w", err)
 		}
 		logger.V(0).Info("created resource")
-		return true, nil
+		return "create", nil
 	}
 
 	if resource.DisableUpdates {
-		return false, nil
+		return "", nil
 	}
 
 	// Apply Eno patches
 	if resource.Patch != nil {
 		if !resource.NeedsToBePatched(current) {
-			return false, nil
+			return "", nil
 		}
 		patch, err := json.Marshal(&resource.Patch)
 		if err != nil {
-			return false, fmt.Errorf("encoding json patch: %w", err)
+			return "", fmt.Errorf("encoding json patch: %w", err)
 		}
 
 		err = c.upstreamClient.Patch(ctx, current, client.RawPatch(types.JSONPatchType, patch))
 		if err != nil {
-			return false, fmt.Errorf("applying patch: %w", err)
+			return "", fmt.Errorf("applying patch: %w", err)
 		}
 
-		reconciliationActions.WithLabelValues("patch").Inc()
+		c.recordAction("patch")
 		logger.V(0).Info("patched resource", "resourceVersion", current.GetResourceVersion())
-		return true, nil
+		return "patch", nil
 	}
 
 	// Compute a merge patch
 	updated, typed, err := resource.Merge(ctx, prev, current, c.discovery)
 	if err != nil {
-		return false, fmt.Errorf("performing three-way merge: %w", err)
+		return "", fmt.Errorf("performing three-way merge: %w", err)
 	}
 	if updated == nil {
 		logger.V(1).Info("skipping empty update")
-		return false, nil
+		return "", nil
 	}
 	if insecureLogPatch {
 		js, _ := updated.MarshalJSON()
@@ -296,13 +379,118 @@ func (c *Controller) reconcileResource(ctx context.Context, comp *apiv1.Composit
 	}
 
 	err = c.upstreamClient.Update(ctx, updated)
+	if resource.Recreate && isImmutableFieldErr(err) {
+		return c.recreate(ctx, current, err)
+	}
 	if err != nil {
-		return false, fmt.Errorf("applying update: %w", err)
+		return "", fmt.Errorf("applying update: %w", err)
 	}
 
-	reconciliationActions.WithLabelValues("patch").Inc()
+	c.recordAction("patch")
 	logger.V(0).Info("updated resource", "resourceVersion", updated.GetResourceVersion(), "previousResourceVersion", current.GetResourceVersion(), "typedMerge", typed)
-	return true, nil
+	return "update", nil
+}
+
+// recreate deletes a resource that can't be updated because the apiserver rejected the update for changing
+// immutable fields. The resource is created again once the deletion completes.
+func (c *Controller) recreate(ctx context.Context, current *unstructured.Unstructured, updateErr error) (string, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+
+	// Foreground deletion keeps the resource around until its dependents have been deleted,
+	// to avoid conflicting with them when it's recreated (e.g. pods with stable names)
+	uid := current.GetUID()
+	err := c.upstreamClient.Delete(ctx, current, client.PropagationPolicy(metav1.DeletePropagationForeground), client.Preconditions{UID: &uid})
+	if client.IgnoreNotFound(err) != nil {
+		return "", fmt.Errorf("deleting resource to recreate it: %w", err)
+	}
+
+	c.recordAction("recreate")
+	logger.V(0).Info("deleted resource to recreate it because the update changes immutable fields", "error", updateErr.Error())
+	return "recreate", nil
+}
+
+// recordAction counts an attempt to reconcile a resource.
+// Actions aren't counted in dry-run mode since they don't modify anything.
+func (c *Controller) recordAction(action string) {
+	if !c.dryRun {
+		reconciliationActions.WithLabelValues(action).Inc()
+	}
+}
+
+// isImmutableFieldErr returns true when the apiserver rejected a write for changing immutable fields.
+// Other validation errors (e.g. a missing required field) can't be resolved by recreating the resource.
+func isImmutableFieldErr(err error) bool {
+	if !apierrors.IsInvalid(err) {
+		return false
+	}
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return false
+	}
+	details := status.Status().Details
+	if details == nil {
+		return false
+	}
+	for _, cause := range details.Causes {
+		if strings.Contains(cause.Message, apimachineryvalidation.FieldImmutableErrorMsg) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply reconciles the resource using server-side apply.
+// Conflicts with other field managers are returned as errors unless force is set.
+func (c *Controller) apply(ctx context.Context, resource *reconstitution.Resource, current *unstructured.Unstructured, force bool) (string, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+
+	obj, err := resource.Parse()
+	if err != nil {
+		return "", fmt.Errorf("invalid resource: %w", err)
+	}
+
+	// Ignored fields are only set at creation, so they're left out of apply requests afterwards to avoid owning them.
+	// Resources that have ignored fields are created without applying them for the same reason:
+	// fields set by an update operation aren't removed when they're omitted from later apply requests.
+	if current != nil {
+		for _, path := range resource.IgnoredFields {
+			unstructured.RemoveNestedField(obj.Object, path...)
+		}
+	} else if len(resource.IgnoredFields) > 0 {
+		err = c.upstreamClient.Create(ctx, obj, client.FieldOwner(fieldManager))
+		if err != nil {
+			return "", fmt.Errorf("creating resource: %w", err)
+		}
+		c.recordAction("create")
+		logger.V(0).Info("created resource", "resourceVersion", obj.GetResourceVersion())
+		return "create", nil
+	}
+
+	opts := []client.PatchOption{client.FieldOwner(fieldManager)}
+	if force {
+		opts = append(opts, client.ForceOwnership)
+	}
+	err = c.upstreamClient.Patch(ctx, obj, client.Apply, opts...)
+	if err != nil {
+		return "", fmt.Errorf("applying resource: %w", err)
+	}
+
+	if current == nil {
+		c.recordAction("create")
+		logger.V(0).Info("created resource", "resourceVersion", obj.GetResourceVersion())
+		return "create", nil
+	}
+
+	// Applying a configuration that doesn't change the resource doesn't bump its resource version.
+	// Dry-run requests never bump it, so the returned state is compared to the current state instead.
+	if (!c.dryRun && obj.GetResourceVersion() == current.GetResourceVersion()) || (c.dryRun && equalIgnoringManagedFields(obj, current)) {
+		logger.V(1).Info("skipping empty apply")
+		return "", nil
+	}
+
+	c.recordAction("apply")
+	logger.V(0).Info("applied resource", "resourceVersion", obj.GetResourceVersion(), "previousResourceVersion", current.GetResourceVersion())
+	return "apply", nil
 }
 
 func (c *Controller) getCurrent(ctx context.Context, resource *reconstitution.Resource) (*unstructured.Unstructured, error) {
@@ -318,19 +506,107 @@ func (c *Controller) getCurrent(ctx context.Context, resource *reconstitution.Re
 	return current, nil
 }
 
-func patchResourceState(deleted bool, ready *metav1.Time) flowcontrol.StatusPatchFn {
+func patchResourceState(deleted bool, ready *metav1.Time, drifted bool, driftedFields []string) flowcontrol.StatusPatchFn {
 	return func(rs *apiv1.ResourceState) *apiv1.ResourceState {
-		if rs != nil && rs.Deleted == deleted && rs.Reconciled && ptr.Deref(rs.Ready, metav1.Time{}) == ptr.Deref(ready, metav1.Time{}) {
+		if rs != nil && rs.Deleted == deleted && rs.Reconciled && ptr.Deref(rs.Ready, metav1.Time{}) == ptr.Deref(ready, metav1.Time{}) &&
+			rs.Drifted == drifted && slices.Equal(rs.DriftedFields, driftedFields) {
 			return nil
 		}
 		return &apiv1.ResourceState{
-			Deleted:    deleted,
-			Ready:      ready,
-			Reconciled: true,
+			Deleted:       deleted,
+			Ready:         ready,
+			Reconciled:    true,
+			Drifted:       drifted,
+			DriftedFields: driftedFields,
 		}
 	}
 }
 
+func patchDeniedResourceState() flowcontrol.StatusPatchFn {
+	return func(rs *apiv1.ResourceState) *apiv1.ResourceState {
+		if rs != nil && rs.Denied {
+			return nil
+		}
+		return &apiv1.ResourceState{Denied: true}
+	}
+}
+
+func patchConflictedResourceState(conflicts []string) flowcontrol.StatusPatchFn {
+	return func(rs *apiv1.ResourceState) *apiv1.ResourceState {
+		if rs != nil && !rs.Reconciled && slices.Equal(rs.Conflicts, conflicts) {
+			return nil
+		}
+		return &apiv1.ResourceState{Conflicts: conflicts}
+	}
+}
+
+func patchRecreatingResourceState() flowcontrol.StatusPatchFn {
+	return func(rs *apiv1.ResourceState) *apiv1.ResourceState {
+		if rs != nil && rs.Equal(&apiv1.ResourceState{}) {
+			return nil
+		}
+		return &apiv1.ResourceState{}
+	}
+}
+
+func patchDryRunResourceState(ready *metav1.Time, action string) flowcontrol.StatusPatchFn {
+	if action == "" {
+		action = "none"
+	}
+	return func(rs *apiv1.ResourceState) *apiv1.ResourceState {
+		// Resource slices are shared with any reconcilers that aren't in dry-run mode, so their state takes precedence
+		if rs != nil && rs.Reconciled {
+			return nil
+		}
+		if rs != nil && rs.DryRunAction == action && ptr.Deref(rs.Ready, metav1.Time{}) == ptr.Deref(ready, metav1.Time{}) {
+			return nil
+		}
+		return &apiv1.ResourceState{
+			Ready:        ready,
+			DryRunAction: action,
+		}
+	}
+}
+
+// equalIgnoringManagedFields returns true when both versions of a resource are semantically equal, ignoring their managed fields.
+func equalIgnoringManagedFields(a, b *unstructured.Unstructured) bool {
+	a, b = a.DeepCopy(), b.DeepCopy()
+	a.SetManagedFields(nil)
+	b.SetManagedFields(nil)
+	return equality.Semantic.DeepEqual(a, b)
+}
+
+// serverSideApply returns true when the resource should be reconciled using server-side apply,
+// and whether conflicts with other field managers should be forced.
+func serverSideApply(resource *reconstitution.Resource) (enabled, force bool) {
+	return ptr.Deref(resource.ServerSideApply, false), resource.ApplyConflicts != apiv1.ConflictPolicyReport
+}
+
+// applyConflicts returns the fields that caused a server-side apply request to fail because they're managed by another field manager.
+// Returns nil for any other error.
+func applyConflicts(err error) []string {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return nil
+	}
+
+	var conflicts []string
+	var n int
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		n++
+		if n <= maxReportedFields {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %s", cause.Field, cause.Message))
+		}
+	}
+	if n > maxReportedFields {
+		conflicts = append(conflicts, fmt.Sprintf("%d more conflicts", n-maxReportedFields))
+	}
+	return conflicts
+}
+
 // isErrMissingNS returns true when given the client-go error returned by mutating requests that do not include a namespace.
 // Sadly, this error isn't exposed anywhere - it's just a plain string, so we have to do string matching here.
 //
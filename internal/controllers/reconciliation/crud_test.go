@@ -370,6 +370,25 @@ func getPhase(obj client.Object) string {
 	return anno["test-phase"]
 }
 
+// newTestConfigMap returns a ConfigMap named name in the default namespace, with foo as the value of its "foo" key.
+func newTestConfigMap(name, foo string, annotations map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]any{
+				"name":      name,
+				"namespace": "default",
+			},
+			"data": map[string]any{"foo": foo},
+		},
+	}
+	if annotations != nil {
+		obj.SetAnnotations(annotations)
+	}
+	return obj
+}
+
 // TestReconcileInterval proves that resources that specify a reconcile interval eventually converge
 // when modified from outside of Eno.
 func TestReconcileInterval(t *testing.T) {
@@ -702,6 +721,52 @@ func TestDisableUpdates(t *testing.T) {
 	assert.Equal(t, "baz", obj.Data["foo"])
 }
 
+// TestIgnoreFields proves that fields listed in the ignore-fields annotation are set at creation, but not updated.
+func TestIgnoreFields(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	mgr := testutil.NewManager(t)
+	upstream := mgr.GetClient()
+	downstream := mgr.DownstreamClient
+
+	// Register supporting controllers
+	registerControllers(t, mgr)
+	testutil.WithFakeExecutor(t, mgr, func(ctx context.Context, s *apiv1.Synthesizer, input *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+		obj := newTestConfigMap("test-obj", "bar", map[string]string{
+			"eno.azure.io/reconcile-interval": "10ms",
+			"eno.azure.io/ignore-fields":      "data.foo",
+		})
+		unstructured.SetNestedField(obj.Object, "qux", "data", "baz")
+		return &krmv1.ResourceList{Items: []*unstructured.Unstructured{obj}}, nil
+	})
+
+	// Test subject
+	setupTestSubject(t, mgr)
+	mgr.Start(t)
+	writeGenericComposition(t, upstream)
+
+	// Wait for resource to be created
+	obj := &corev1.ConfigMap{}
+	testutil.Eventually(t, func() bool {
+		obj.SetName("test-obj")
+		obj.SetNamespace("default")
+		err := downstream.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		return err == nil
+	})
+	assert.Equal(t, "bar", obj.Data["foo"])
+
+	// Update both fields from outside of Eno
+	obj.Data["foo"] = "changed"
+	obj.Data["baz"] = "changed"
+	require.NoError(t, downstream.Update(ctx, obj))
+
+	// Only the field that isn't ignored should be corrected
+	testutil.Eventually(t, func() bool {
+		err := downstream.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		return err == nil && obj.Data["baz"] == "qux"
+	})
+	assert.Equal(t, "changed", obj.Data["foo"])
+}
+
 // TestOrphanedCompositionDeletion proves that compositions can be deleted when their synthesizer is missing.
 func TestOrphanedCompositionDeletion(t *testing.T) {
 	scheme := runtime.NewScheme()
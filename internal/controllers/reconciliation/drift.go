@@ -0,0 +1,71 @@
+package reconciliation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/Azure/eno/internal/reconstitution"
+)
+
+// detectDrift compares the resource's current state to its desired state without modifying it.
+// Returns the paths of the fields that have drifted, which may be empty even when drift is detected (e.g. for patches).
+func (c *Controller) detectDrift(ctx context.Context, prev, resource *reconstitution.Resource, current *unstructured.Unstructured) (bool, []string, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+
+	var fields []string
+	if resource.Patch != nil {
+		if !resource.NeedsToBePatched(current) {
+			return false, nil, nil
+		}
+	} else {
+		updated, _, err := resource.Merge(ctx, prev, current, c.discovery)
+		if err != nil {
+			return false, nil, fmt.Errorf("performing three-way merge: %w", err)
+		}
+		if updated == nil {
+			return false, nil, nil
+		}
+		fields = diffPaths(current, updated)
+	}
+
+	logger.V(0).Info("detected drift in observe-only resource", "fields", fields)
+
+	if len(fields) > maxReportedFields {
+		fields = append(fields[:maxReportedFields], fmt.Sprintf("%d more fields", len(fields)-maxReportedFields))
+	}
+	return true, fields, nil
+}
+
+// diffPaths returns the sorted paths of the fields that differ between two versions of a resource.
+// Lists are compared as a whole.
+func diffPaths(a, b *unstructured.Unstructured) []string {
+	var paths []string
+	appendDiffPaths(&paths, "", a.Object, b.Object)
+	sort.Strings(paths)
+	return paths
+}
+
+func appendDiffPaths(paths *[]string, prefix string, a, b any) {
+	am, aok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+	if !aok || !bok {
+		if !equality.Semantic.DeepEqual(a, b) {
+			*paths = append(*paths, prefix)
+		}
+		return
+	}
+
+	for key, av := range am {
+		appendDiffPaths(paths, prefix+"."+key, av, bm[key])
+	}
+	for key := range bm {
+		if _, ok := am[key]; !ok {
+			*paths = append(*paths, prefix+"."+key)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package reconciliation
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/Azure/eno/internal/testutil"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+)
+
+func TestDiffPaths(t *testing.T) {
+	a := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "foo", "labels": map[string]any{"a": "b"}},
+		"spec": map[string]any{
+			"replicas": int64(1),
+			"list":     []any{"a", "b"},
+			"removed":  "value",
+		},
+	}}
+	b := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "foo", "labels": map[string]any{"a": "c"}},
+		"spec": map[string]any{
+			"replicas": int64(2),
+			"list":     []any{"a"},
+			"added":    map[string]any{"nested": true},
+		},
+	}}
+
+	assert.Equal(t, []string{".metadata.labels.a", ".spec.added", ".spec.list", ".spec.removed", ".spec.replicas"}, diffPaths(a, b))
+	assert.Empty(t, diffPaths(a, a))
+}
+
+// TestObserveOnly proves that drift is reported instead of corrected for compositions in observe-only mode,
+// and that drift is corrected once the composition is switched back to enforcing mode.
+func TestObserveOnly(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	mgr := testutil.NewManager(t)
+	upstream := mgr.GetClient()
+	downstream := mgr.DownstreamClient
+
+	registerControllers(t, mgr)
+	testutil.WithFakeExecutor(t, mgr, func(ctx context.Context, s *apiv1.Synthesizer, input *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+		return &krmv1.ResourceList{Items: []*unstructured.Unstructured{
+			newTestConfigMap("test-obj", "bar", map[string]string{"eno.azure.io/reconcile-interval": "10ms"}),
+		}}, nil
+	})
+
+	// Test subject
+	setupTestSubject(t, mgr)
+	mgr.Start(t)
+
+	syn := &apiv1.Synthesizer{}
+	syn.Name = "test-syn"
+	syn.Spec.Image = "create"
+	require.NoError(t, upstream.Create(ctx, syn))
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Annotations = map[string]string{"eno.azure.io/observe-only": "true"}
+	comp.Spec.Synthesizer.Name = syn.Name
+	require.NoError(t, upstream.Create(ctx, comp))
+
+	// Missing resources are still created
+	obj := &corev1.ConfigMap{}
+	obj.SetName("test-obj")
+	obj.SetNamespace("default")
+	testutil.Eventually(t, func() bool {
+		return downstream.Get(ctx, client.ObjectKeyFromObject(obj), obj) == nil
+	})
+
+	// Drift should be reported but not corrected
+	obj.Data["foo"] = "baz"
+	require.NoError(t, downstream.Update(ctx, obj))
+
+	testutil.Eventually(t, func() bool {
+		err := upstream.Get(ctx, client.ObjectKeyFromObject(comp), comp)
+		if err != nil || comp.Status.CurrentSynthesis == nil || len(comp.Status.CurrentSynthesis.ResourceSlices) == 0 {
+			return false
+		}
+		slice := &apiv1.ResourceSlice{}
+		slice.Name = comp.Status.CurrentSynthesis.ResourceSlices[0].Name
+		slice.Namespace = comp.Namespace
+		err = upstream.Get(ctx, client.ObjectKeyFromObject(slice), slice)
+		return err == nil && len(slice.Status.Resources) > 0 && slice.Status.Resources[0].Drifted &&
+			slices.Equal([]string{".data.foo"}, slice.Status.Resources[0].DriftedFields)
+	})
+
+	time.Sleep(time.Millisecond * 100)
+	require.NoError(t, downstream.Get(ctx, client.ObjectKeyFromObject(obj), obj))
+	assert.Equal(t, "baz", obj.Data["foo"])
+
+	// Drift should be corrected once the composition is enforcing again
+	err := retry.RetryOnConflict(testutil.Backoff, func() error {
+		upstream.Get(ctx, client.ObjectKeyFromObject(comp), comp)
+		delete(comp.Annotations, "eno.azure.io/observe-only")
+		return upstream.Update(ctx, comp)
+	})
+	require.NoError(t, err)
+
+	testutil.Eventually(t, func() bool {
+		err := downstream.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		return err == nil && obj.Data["foo"] == "bar"
+	})
+}
@@ -0,0 +1,111 @@
+package reconciliation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/Azure/eno/internal/flowcontrol"
+	"github.com/Azure/eno/internal/reconstitution"
+	"github.com/Azure/eno/internal/testutil"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+)
+
+// TestDryRun proves that the reconciler doesn't modify resources in dry-run mode,
+// and records the actions it would have taken instead of marking the resources as reconciled.
+func TestDryRun(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	mgr := testutil.NewManager(t)
+	upstream := mgr.GetClient()
+	downstream := mgr.DownstreamClient
+
+	registerControllers(t, mgr)
+	testutil.WithFakeExecutor(t, mgr, func(ctx context.Context, s *apiv1.Synthesizer, input *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+		return &krmv1.ResourceList{Items: []*unstructured.Unstructured{
+			newTestConfigMap("new", "bar", nil),
+			newTestConfigMap("existing", "bar", nil),
+		}}, nil
+	})
+
+	// Test subject
+	cache := reconstitution.NewCache(mgr.GetClient())
+	rc, err := New(Options{
+		Manager:               mgr.Manager,
+		Cache:                 cache,
+		WriteBuffer:           flowcontrol.NewResourceSliceWriteBufferForManager(mgr.Manager),
+		Downstream:            mgr.DownstreamRestConfig,
+		DiscoveryRPS:          5,
+		Timeout:               time.Minute,
+		ReadinessPollInterval: time.Hour,
+		DryRun:                true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, reconstitution.New(mgr.Manager, cache, rc))
+	mgr.Start(t)
+
+	existing := &corev1.ConfigMap{}
+	existing.Name = "existing"
+	existing.Namespace = "default"
+	existing.Data = map[string]string{"foo": "baz"}
+	require.NoError(t, downstream.Create(ctx, existing))
+
+	_, comp := writeGenericComposition(t, upstream)
+
+	// The intended actions should be recorded
+	actions := map[string]string{}
+	testutil.Eventually(t, func() bool {
+		err := upstream.Get(ctx, client.ObjectKeyFromObject(comp), comp)
+		if err != nil || comp.Status.CurrentSynthesis == nil || len(comp.Status.CurrentSynthesis.ResourceSlices) == 0 {
+			return false
+		}
+		slice := &apiv1.ResourceSlice{}
+		slice.Name = comp.Status.CurrentSynthesis.ResourceSlices[0].Name
+		slice.Namespace = comp.Namespace
+		err = upstream.Get(ctx, client.ObjectKeyFromObject(slice), slice)
+		if err != nil || len(slice.Status.Resources) != len(slice.Spec.Resources) {
+			return false
+		}
+		for i, manifest := range slice.Spec.Resources {
+			obj := &unstructured.Unstructured{}
+			require.NoError(t, json.Unmarshal([]byte(manifest.Manifest), obj))
+			state := slice.Status.Resources[i]
+			if state.DryRunAction == "" {
+				return false
+			}
+			assert.False(t, state.Reconciled)
+			actions[obj.GetName()] = state.DryRunAction
+		}
+		return true
+	})
+	assert.Equal(t, map[string]string{"new": "create", "existing": "update"}, actions)
+
+	// Nothing should have been modified
+	err = downstream.Get(ctx, client.ObjectKeyFromObject(existing), existing)
+	require.NoError(t, err)
+	assert.Equal(t, "baz", existing.Data["foo"])
+
+	err = downstream.Get(ctx, client.ObjectKeyFromObject(newTestConfigMap("new", "bar", nil)), &corev1.ConfigMap{})
+	assert.True(t, errors.IsNotFound(err))
+
+	require.NoError(t, upstream.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	assert.Nil(t, comp.Status.CurrentSynthesis.Reconciled)
+}
+
+func TestPatchDryRunResourceState(t *testing.T) {
+	patch := patchDryRunResourceState(nil, "")
+	assert.Equal(t, &apiv1.ResourceState{DryRunAction: "none"}, patch(nil))
+	assert.Nil(t, patch(&apiv1.ResourceState{DryRunAction: "none"}))
+	assert.Equal(t, &apiv1.ResourceState{DryRunAction: "none"}, patch(&apiv1.ResourceState{DryRunAction: "create"}))
+
+	// State written by reconcilers that aren't in dry-run mode is left as-is
+	assert.Nil(t, patch(&apiv1.ResourceState{Reconciled: true}))
+}
@@ -27,7 +27,7 @@ func registerControllers(t *testing.T, mgr *testutil.Manager) {
 	require.NoError(t, replication.NewSymphonyController(mgr.Manager))
 	require.NoError(t, aggregation.NewSymphonyController(mgr.Manager))
 	require.NoError(t, aggregation.NewCompositionController(mgr.Manager))
-	require.NoError(t, scheduling.NewController(mgr.Manager, 10, time.Millisecond))
+	require.NoError(t, scheduling.NewController(mgr.Manager, &scheduling.Config{ConcurrencyLimit: 10, CooldownPeriod: time.Millisecond}))
 	require.NoError(t, liveness.NewNamespaceController(mgr.Manager, 3, time.Second))
 	require.NoError(t, watch.NewController(mgr.Manager))
 	require.NoError(t, selfhealing.NewSliceController(mgr.Manager, time.Minute*5))
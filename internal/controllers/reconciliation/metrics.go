@@ -24,10 +24,17 @@ var (
 	reconciliationActions = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "eno_reconciliation_actions_total",
-			Help: "Attempts to reconcile managed resources into the desired state, partitioned by action i.e. create, patch, delete",
+			Help: "Attempts to reconcile managed resources into the desired state, partitioned by action i.e. create, patch, apply, delete, recreate",
 		}, []string{"action"},
 	)
 
+	driftDetections = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "eno_drift_detections_total",
+			Help: "Cases where an observe-only resource that was previously in sync drifted from its desired state",
+		},
+	)
+
 	reconciliationScheduleDelta = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Name:    "eno_reconciliation_schedule_delta_seconds",
@@ -38,5 +45,5 @@ var (
 )
 
 func init() {
-	metrics.Registry.MustRegister(reconciliationLatency, resourceVersionChanges, reconciliationActions, reconciliationScheduleDelta)
+	metrics.Registry.MustRegister(reconciliationLatency, resourceVersionChanges, reconciliationActions, driftDetections, reconciliationScheduleDelta)
 }
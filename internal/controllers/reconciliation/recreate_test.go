@@ -0,0 +1,83 @@
+package reconciliation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/Azure/eno/internal/testutil"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+)
+
+// TestRecreate proves that resources using the recreate update strategy are deleted and recreated
+// when their updates are rejected as invalid i.e. when immutable fields are changed.
+func TestRecreate(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	mgr := testutil.NewManager(t)
+	upstream := mgr.GetClient()
+	downstream := mgr.DownstreamClient
+
+	registerControllers(t, mgr)
+	testutil.WithFakeExecutor(t, mgr, func(ctx context.Context, s *apiv1.Synthesizer, input *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+		obj := newTestConfigMap("test-obj", s.Spec.Image, map[string]string{"eno.azure.io/update-strategy": "recreate"})
+		obj.Object["immutable"] = true
+		return &krmv1.ResourceList{Items: []*unstructured.Unstructured{obj}}, nil
+	})
+
+	// Test subject
+	setupTestSubject(t, mgr)
+	mgr.Start(t)
+	syn, comp := writeGenericComposition(t, upstream)
+
+	// Creation
+	testutil.Eventually(t, func() bool {
+		err := upstream.Get(ctx, client.ObjectKeyFromObject(comp), comp)
+		return err == nil && comp.Status.CurrentSynthesis != nil && comp.Status.CurrentSynthesis.Ready != nil && comp.Status.CurrentSynthesis.ObservedSynthesizerGeneration == syn.Generation
+	})
+
+	cm := &corev1.ConfigMap{}
+	cm.Name = "test-obj"
+	cm.Namespace = "default"
+	require.NoError(t, downstream.Get(ctx, client.ObjectKeyFromObject(cm), cm))
+	assert.Equal(t, "create", cm.Data["foo"])
+	originalUID := cm.UID
+
+	// Change the immutable data
+	err := retry.RetryOnConflict(testutil.Backoff, func() error {
+		upstream.Get(ctx, client.ObjectKeyFromObject(syn), syn)
+		syn.Spec.Image = "updated"
+		return upstream.Update(ctx, syn)
+	})
+	require.NoError(t, err)
+
+	testutil.Eventually(t, func() bool {
+		err := upstream.Get(ctx, client.ObjectKeyFromObject(comp), comp)
+		return err == nil && comp.Status.CurrentSynthesis != nil && comp.Status.CurrentSynthesis.Ready != nil && comp.Status.CurrentSynthesis.ObservedSynthesizerGeneration == syn.Generation
+	})
+
+	// Prove the resource was recreated
+	require.NoError(t, downstream.Get(ctx, client.ObjectKeyFromObject(cm), cm))
+	assert.Equal(t, "updated", cm.Data["foo"])
+	assert.NotEqual(t, originalUID, cm.UID)
+}
+
+func TestIsImmutableFieldErr(t *testing.T) {
+	gk := schema.GroupKind{Kind: "ConfigMap"}
+	assert.False(t, isImmutableFieldErr(nil))
+	assert.False(t, isImmutableFieldErr(fmt.Errorf("some other error")))
+	assert.False(t, isImmutableFieldErr(apierrors.NewInvalid(gk, "test", field.ErrorList{field.Required(field.NewPath("data"), "")})))
+
+	err := apierrors.NewInvalid(gk, "test", field.ErrorList{field.Forbidden(field.NewPath("data"), "field is immutable when `immutable` is set")})
+	assert.True(t, isImmutableFieldErr(fmt.Errorf("applying resource: %w", err)))
+}
@@ -0,0 +1,253 @@
+package reconciliation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/Azure/eno/internal/reconstitution"
+	"github.com/Azure/eno/internal/testutil"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+)
+
+func TestServerSideApplyConfig(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Resource *reconstitution.Resource
+		Enabled  bool
+		Force    bool
+	}{
+		{
+			Name:     "disabled",
+			Resource: &reconstitution.Resource{},
+			Enabled:  false,
+			Force:    true,
+		},
+		{
+			Name:     "default-conflicts",
+			Resource: &reconstitution.Resource{ServerSideApply: ptr.To(true)},
+			Enabled:  true,
+			Force:    true,
+		},
+		{
+			Name:     "report",
+			Resource: &reconstitution.Resource{ServerSideApply: ptr.To(true), ApplyConflicts: apiv1.ConflictPolicyReport},
+			Enabled:  true,
+			Force:    false,
+		},
+		{
+			Name:     "explicitly-disabled",
+			Resource: &reconstitution.Resource{ServerSideApply: ptr.To(false)},
+			Enabled:  false,
+			Force:    true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			enabled, force := serverSideApply(tc.Resource)
+			assert.Equal(t, tc.Enabled, enabled, "enabled")
+			assert.Equal(t, tc.Force, force, "force")
+		})
+	}
+}
+
+func TestApplyConflicts(t *testing.T) {
+	assert.Nil(t, applyConflicts(nil))
+	assert.Nil(t, applyConflicts(fmt.Errorf("some other error")))
+	assert.Nil(t, applyConflicts(apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "test", fmt.Errorf("resource version mismatch"))))
+
+	err := apierrors.NewApplyConflict([]metav1.StatusCause{
+		{Type: metav1.CauseTypeFieldManagerConflict, Field: ".data.foo", Message: `conflict with "other"`},
+		{Type: metav1.CauseTypeFieldValueInvalid, Field: ".data.bar", Message: "unrelated"},
+	}, "conflicts")
+	assert.Equal(t, []string{`.data.foo: conflict with "other"`}, applyConflicts(fmt.Errorf("applying resource: %w", err)))
+
+	var causes []metav1.StatusCause
+	for i := 0; i < maxReportedFields+2; i++ {
+		causes = append(causes, metav1.StatusCause{Type: metav1.CauseTypeFieldManagerConflict, Field: fmt.Sprintf(".data.key-%d", i)})
+	}
+	conflicts := applyConflicts(apierrors.NewApplyConflict(causes, "conflicts"))
+	require.Len(t, conflicts, maxReportedFields+1)
+	assert.Equal(t, "2 more conflicts", conflicts[maxReportedFields])
+}
+
+// TestServerSideApply proves that resources can be reconciled using server-side apply,
+// taking ownership of fields modified by other field managers while preserving the fields they own exclusively.
+func TestServerSideApply(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	mgr := testutil.NewManager(t)
+	upstream := mgr.GetClient()
+	downstream := mgr.DownstreamClient
+
+	registerControllers(t, mgr)
+	testutil.WithFakeExecutor(t, mgr, func(ctx context.Context, s *apiv1.Synthesizer, input *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+		return &krmv1.ResourceList{Items: []*unstructured.Unstructured{newTestConfigMap("test-obj", "bar", map[string]string{"eno.azure.io/reconcile-interval": "10ms"})}}, nil
+	})
+
+	// Test subject
+	setupTestSubject(t, mgr)
+	mgr.Start(t)
+
+	syn := &apiv1.Synthesizer{}
+	syn.Name = "test-syn"
+	syn.Spec.Image = "create"
+	syn.Spec.ServerSideApply = &apiv1.ServerSideApply{Conflicts: apiv1.ConflictPolicyForce}
+	require.NoError(t, upstream.Create(ctx, syn))
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Spec.Synthesizer.Name = syn.Name
+	require.NoError(t, upstream.Create(ctx, comp))
+
+	// The resource should be managed by the eno field manager
+	obj := &corev1.ConfigMap{}
+	obj.SetName("test-obj")
+	obj.SetNamespace("default")
+	testutil.Eventually(t, func() bool {
+		err := downstream.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		return err == nil && slices.ContainsFunc(obj.ManagedFields, func(f metav1.ManagedFieldsEntry) bool {
+			return f.Manager == fieldManager && f.Operation == metav1.ManagedFieldsOperationApply
+		})
+	})
+
+	// Another field manager takes ownership of a field managed by Eno, and adds one of its own
+	other := newTestConfigMap("test-obj", "baz", nil)
+	unstructured.SetNestedField(other.Object, "value", "data", "other")
+	require.NoError(t, downstream.Patch(ctx, other, client.Apply, client.FieldOwner("other"), client.ForceOwnership))
+
+	// Eno should force ownership of its field without removing the other
+	testutil.Eventually(t, func() bool {
+		err := downstream.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		return err == nil && obj.Data["foo"] == "bar" && obj.Data["other"] == "value"
+	})
+}
+
+// TestServerSideApplyConflicts proves that server-side apply conflicts are reported in the resource's status
+// instead of being forced when configured to do so.
+func TestServerSideApplyConflicts(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	mgr := testutil.NewManager(t)
+	upstream := mgr.GetClient()
+	downstream := mgr.DownstreamClient
+
+	registerControllers(t, mgr)
+	testutil.WithFakeExecutor(t, mgr, func(ctx context.Context, s *apiv1.Synthesizer, input *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+		obj := newTestConfigMap("test-obj", "bar", map[string]string{"eno.azure.io/server-side-apply-conflicts": "Report"})
+		return &krmv1.ResourceList{Items: []*unstructured.Unstructured{obj}}, nil
+	})
+
+	// Test subject
+	setupTestSubject(t, mgr)
+	mgr.Start(t)
+
+	// Another field manager owns the field before Eno gets to it
+	other := newTestConfigMap("test-obj", "baz", nil)
+	require.NoError(t, downstream.Patch(ctx, other, client.Apply, client.FieldOwner("other")))
+
+	syn := &apiv1.Synthesizer{}
+	syn.Name = "test-syn"
+	syn.Spec.Image = "create"
+	syn.Spec.ServerSideApply = &apiv1.ServerSideApply{}
+	require.NoError(t, upstream.Create(ctx, syn))
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Spec.Synthesizer.Name = syn.Name
+	require.NoError(t, upstream.Create(ctx, comp))
+
+	// The conflict should be reported in the resource's status
+	var conflicts []string
+	testutil.Eventually(t, func() bool {
+		err := upstream.Get(ctx, client.ObjectKeyFromObject(comp), comp)
+		if err != nil || comp.Status.CurrentSynthesis == nil || len(comp.Status.CurrentSynthesis.ResourceSlices) == 0 {
+			return false
+		}
+		slice := &apiv1.ResourceSlice{}
+		slice.Name = comp.Status.CurrentSynthesis.ResourceSlices[0].Name
+		slice.Namespace = comp.Namespace
+		err = upstream.Get(ctx, client.ObjectKeyFromObject(slice), slice)
+		if err != nil || len(slice.Status.Resources) == 0 {
+			return false
+		}
+		conflicts = slice.Status.Resources[0].Conflicts
+		return len(conflicts) > 0
+	})
+	require.Len(t, conflicts, 1)
+	assert.True(t, strings.HasPrefix(conflicts[0], ".data.foo: "), conflicts[0])
+
+	// The conflicting field should not have been modified
+	obj := &corev1.ConfigMap{}
+	obj.SetName("test-obj")
+	obj.SetNamespace("default")
+	require.NoError(t, downstream.Get(ctx, client.ObjectKeyFromObject(obj), obj))
+	assert.Equal(t, "baz", obj.Data["foo"])
+}
+
+// TestServerSideApplyIgnoreFields proves that ignored fields are set at creation but not owned by Eno's apply configuration,
+// so they're neither removed nor reverted when they're modified by other field managers.
+func TestServerSideApplyIgnoreFields(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	mgr := testutil.NewManager(t)
+	upstream := mgr.GetClient()
+	downstream := mgr.DownstreamClient
+
+	registerControllers(t, mgr)
+	testutil.WithFakeExecutor(t, mgr, func(ctx context.Context, s *apiv1.Synthesizer, input *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+		obj := newTestConfigMap("test-obj", "bar", map[string]string{
+			"eno.azure.io/reconcile-interval": "10ms",
+			"eno.azure.io/server-side-apply":  "true",
+			"eno.azure.io/ignore-fields":      "data.foo",
+		})
+		unstructured.SetNestedField(obj.Object, "qux", "data", "baz")
+		return &krmv1.ResourceList{Items: []*unstructured.Unstructured{obj}}, nil
+	})
+
+	// Test subject
+	setupTestSubject(t, mgr)
+	mgr.Start(t)
+	writeGenericComposition(t, upstream)
+
+	// Wait for the resource to be applied after creation
+	obj := &corev1.ConfigMap{}
+	obj.SetName("test-obj")
+	obj.SetNamespace("default")
+	testutil.Eventually(t, func() bool {
+		err := downstream.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		return err == nil && slices.ContainsFunc(obj.ManagedFields, func(f metav1.ManagedFieldsEntry) bool {
+			return f.Manager == fieldManager && f.Operation == metav1.ManagedFieldsOperationApply
+		})
+	})
+	assert.Equal(t, "bar", obj.Data["foo"])
+	for _, f := range obj.ManagedFields {
+		if f.Operation == metav1.ManagedFieldsOperationApply {
+			assert.NotContains(t, string(f.FieldsV1.Raw), `"f:foo"`)
+		}
+	}
+
+	// Update both fields from outside of Eno
+	obj.Data["foo"] = "changed"
+	obj.Data["baz"] = "changed"
+	require.NoError(t, downstream.Update(ctx, obj))
+
+	// Only the field that isn't ignored should be corrected
+	testutil.Eventually(t, func() bool {
+		err := downstream.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		return err == nil && obj.Data["baz"] == "qux"
+	})
+	assert.Equal(t, "changed", obj.Data["foo"])
+}
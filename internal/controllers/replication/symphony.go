@@ -2,6 +2,7 @@ package replication
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"slices"
 	"sort"
@@ -13,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -171,9 +173,15 @@ func (c *symphonyController) reconcileForward(ctx context.Context, symph *apiv1.
 		comp.Spec.Bindings = getBindings(symph, &variation)
 		comp.Spec.Synthesizer = variation.Synthesizer
 		comp.Spec.SynthesisEnv = symph.Spec.SynthesisEnv
+		comp.Spec.Priority = variation.Priority
 		comp.Labels = variation.Labels
 		comp.Annotations = variation.Annotations
-		err := controllerutil.SetControllerReference(symph, comp, c.client.Scheme())
+		values, err := getValues(symph, &variation)
+		if err != nil {
+			return false, fmt.Errorf("merging values: %w", err)
+		}
+		comp.Spec.Values = values
+		err = controllerutil.SetControllerReference(symph, comp, c.client.Scheme())
 		if err != nil {
 			return false, fmt.Errorf("setting composition's controller: %w", err)
 		}
@@ -181,6 +189,7 @@ func (c *symphonyController) reconcileForward(ctx context.Context, symph *apiv1.
 		// Diff and update if needed when the composition for this synthesizer already exists
 		if existings, ok := existingBySynthName[variation.Synthesizer.Name]; ok {
 			existing := existings[0]
+			existing.Spec.Values = normalizeValues(existing.Spec.Values) // only semantic changes should cause an update
 			if equality.Semantic.DeepEqual(comp.Spec, existing.Spec) && !coalesceMetadata(&variation, existing) {
 				continue // already matches
 			}
@@ -279,6 +288,61 @@ func getBindings(symph *apiv1.Symphony, vrn *apiv1.Variation) []apiv1.Binding {
 	return deduped
 }
 
+// getValues generates the values for a variation given its symphony.
+// Values specified by a variation are deep merged with the symphony's values and take precedence over them.
+func getValues(symph *apiv1.Symphony, vrn *apiv1.Variation) (*runtime.RawExtension, error) {
+	if symph.Spec.Values == nil && vrn.Values == nil {
+		return nil, nil
+	}
+
+	merged := map[string]any{}
+	for _, values := range []*runtime.RawExtension{symph.Spec.Values, vrn.Values} {
+		if values == nil {
+			continue
+		}
+		m := map[string]any{}
+		if err := json.Unmarshal(values.Raw, &m); err != nil {
+			return nil, err
+		}
+		mergeValues(merged, m)
+	}
+
+	js, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: js}, nil
+}
+
+// mergeValues recursively merges src into dst. Only objects are merged, other values (including lists) are replaced.
+func mergeValues(dst, src map[string]any) {
+	for key, val := range src {
+		srcObj, srcOk := val.(map[string]any)
+		dstObj, dstOk := dst[key].(map[string]any)
+		if srcOk && dstOk {
+			mergeValues(dstObj, srcObj)
+			continue
+		}
+		dst[key] = val
+	}
+}
+
+// normalizeValues re-encodes values the same way as getValues so they can be compared.
+func normalizeValues(values *runtime.RawExtension) *runtime.RawExtension {
+	if values == nil {
+		return nil
+	}
+	var m any
+	if err := json.Unmarshal(values.Raw, &m); err != nil {
+		return values
+	}
+	js, err := json.Marshal(m)
+	if err != nil {
+		return values
+	}
+	return &runtime.RawExtension{Raw: js}
+}
+
 func sortSynthesizerRefs(refs []apiv1.SynthesizerRef) {
 	sort.Slice(refs, func(i, j int) bool { return refs[i].Name < refs[j].Name })
 }
@@ -13,8 +13,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 func TestSymphonyCRUD(t *testing.T) {
@@ -282,6 +284,68 @@ func TestGetBindings(t *testing.T) {
 	}
 }
 
+func TestGetValues(t *testing.T) {
+	tcs := []struct {
+		name           string
+		symph          string
+		variation      string
+		expectedValues string
+	}{
+		{
+			name: "no values",
+		},
+		{
+			name:           "just symphony values",
+			symph:          `{"foo": "bar"}`,
+			expectedValues: `{"foo":"bar"}`,
+		},
+		{
+			name:           "just variation values",
+			variation:      `{"foo": "bar"}`,
+			expectedValues: `{"foo":"bar"}`,
+		},
+		{
+			name:           "variation takes precedence over symphony",
+			symph:          `{"foo": "from-symphony", "bar": "baz"}`,
+			variation:      `{"foo": "from-variation"}`,
+			expectedValues: `{"bar":"baz","foo":"from-variation"}`,
+		},
+		{
+			name:           "objects are deep merged",
+			symph:          `{"nested": {"foo": 1, "bar": 2, "list": [1, 2]}}`,
+			variation:      `{"nested": {"bar": 3, "list": [3]}}`,
+			expectedValues: `{"nested":{"bar":3,"foo":1,"list":[3]}}`,
+		},
+		{
+			name:           "objects replace other types",
+			symph:          `{"foo": "bar"}`,
+			variation:      `{"foo": {"bar": "baz"}}`,
+			expectedValues: `{"foo":{"bar":"baz"}}`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			symph := &apiv1.Symphony{}
+			if tc.symph != "" {
+				symph.Spec.Values = &runtime.RawExtension{Raw: []byte(tc.symph)}
+			}
+			variation := &apiv1.Variation{}
+			if tc.variation != "" {
+				variation.Values = &runtime.RawExtension{Raw: []byte(tc.variation)}
+			}
+
+			actual, err := getValues(symph, variation)
+			require.NoError(t, err)
+			if tc.expectedValues == "" {
+				assert.Nil(t, actual)
+				return
+			}
+			require.NotNil(t, actual)
+			assert.Equal(t, tc.expectedValues, string(actual.Raw))
+		})
+	}
+}
+
 func TestCoalesceMetadata(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -532,3 +596,51 @@ func TestReconcileForward_CreateComposition(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, compositions.Items, 1)
 }
+
+func TestReconcileForward_Values(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-namespace",
+		},
+	}
+
+	symph := &apiv1.Symphony{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-symph",
+			Namespace: "test-namespace",
+		},
+		Spec: apiv1.SymphonySpec{
+			Values: &runtime.RawExtension{Raw: []byte(`{"foo": "bar", "baz": 1}`)},
+			Variations: []apiv1.Variation{{
+				Synthesizer: apiv1.SynthesizerRef{Name: "test-synth"},
+				Values:      &runtime.RawExtension{Raw: []byte(`{"foo": "qux"}`)},
+			}},
+		},
+	}
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = ns.Name
+	comp.Spec.Synthesizer.Name = "test-synth"
+	comp.Spec.Values = &runtime.RawExtension{Raw: []byte(`{ "foo": "qux", "baz": 1 }`)}
+
+	cli := testutil.NewClient(t, ns, symph)
+	controller := &symphonyController{client: cli, reader: cli}
+
+	ctx := context.Background()
+	existingBySynthName := map[string][]*apiv1.Composition{"test-synth": {comp}}
+	modified, err := controller.reconcileForward(ctx, symph, existingBySynthName)
+	require.NoError(t, err)
+	assert.False(t, modified, "formatting differences don't cause an update")
+
+	comp.Spec.Values = &runtime.RawExtension{Raw: []byte(`{"foo": "bar", "baz": 1}`)}
+	require.NoError(t, controllerutil.SetControllerReference(symph, comp, cli.Scheme()))
+	require.NoError(t, cli.Create(ctx, comp))
+
+	modified, err = controller.reconcileForward(ctx, symph, existingBySynthName)
+	require.NoError(t, err)
+	assert.True(t, modified)
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	assert.JSONEq(t, `{"foo": "qux", "baz": 1}`, string(comp.Spec.Values.Raw))
+}
@@ -0,0 +1,132 @@
+package revision
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/Azure/eno/internal/manager"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// controller captures an immutable SynthesizerRevision for every synthesizer generation it observes.
+//
+// Revisions beyond the history limit are pruned (oldest first) unless a composition is pinned to them.
+// Revisions are owned by their synthesizer, so they're garbage collected when it's deleted.
+type controller struct {
+	client       client.Client
+	historyLimit int
+}
+
+func NewController(mgr ctrl.Manager, historyLimit int) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1.Synthesizer{}).
+		Owns(&apiv1.SynthesizerRevision{}).
+		WithLogConstructor(manager.NewLogConstructor(mgr, "synthesizerRevisionController")).
+		Complete(&controller{
+			client:       mgr.GetClient(),
+			historyLimit: max(historyLimit, 1),
+		})
+}
+
+func (c *controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+
+	synth := &apiv1.Synthesizer{}
+	err := c.client.Get(ctx, req.NamespacedName, synth)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(fmt.Errorf("getting synthesizer: %w", err))
+	}
+	if synth.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+	logger = logger.WithValues("synthesizerName", synth.Name, "synthesizerGeneration", synth.Generation)
+
+	current := &apiv1.SynthesizerRevision{}
+	current.Name = apiv1.SynthesizerRevisionName(synth.Name, synth.Generation)
+	err = c.client.Get(ctx, client.ObjectKeyFromObject(current), current)
+	if errors.IsNotFound(err) {
+		return ctrl.Result{}, c.captureRevision(ctx, synth)
+	}
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("getting current revision: %w", err)
+	}
+
+	revs := &apiv1.SynthesizerRevisionList{}
+	err = c.client.List(ctx, revs)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("listing revisions: %w", err)
+	}
+	var owned []apiv1.SynthesizerRevision
+	for _, rev := range revs.Items {
+		if rev.Spec.Synthesizer == synth.Name && rev.Spec.Generation < synth.Generation {
+			owned = append(owned, rev)
+		}
+	}
+	if len(owned) < c.historyLimit {
+		return ctrl.Result{}, nil
+	}
+
+	pinned, err := c.getPinnedRevisions(ctx, synth)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The current revision always counts towards the limit
+	sort.Slice(owned, func(i, j int) bool { return owned[i].Spec.Generation > owned[j].Spec.Generation })
+	for _, rev := range owned[c.historyLimit-1:] {
+		if _, ok := pinned[rev.Spec.Generation]; ok {
+			continue
+		}
+		if err := c.client.Delete(ctx, &rev); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("deleting revision: %w", err)
+		}
+		logger.V(0).Info("pruned synthesizer revision", "revisionName", rev.Name, "revisionGeneration", rev.Spec.Generation)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (c *controller) captureRevision(ctx context.Context, synth *apiv1.Synthesizer) error {
+	rev := &apiv1.SynthesizerRevision{}
+	rev.Name = apiv1.SynthesizerRevisionName(synth.Name, synth.Generation)
+	rev.Spec.Synthesizer = synth.Name
+	rev.Spec.Generation = synth.Generation
+	synth.Spec.DeepCopyInto(&rev.Spec.SynthesizerSpec)
+	if err := controllerutil.SetOwnerReference(synth, rev, c.client.Scheme()); err != nil {
+		return fmt.Errorf("setting owner reference: %w", err)
+	}
+
+	err := c.client.Create(ctx, rev)
+	if errors.IsAlreadyExists(err) {
+		return nil // the informer cache is stale
+	}
+	if err != nil {
+		return fmt.Errorf("creating revision: %w", err)
+	}
+	logr.FromContextOrDiscard(ctx).V(0).Info("captured synthesizer revision", "revisionName", rev.Name)
+	return nil
+}
+
+// getPinnedRevisions returns the generations of the given synthesizer that compositions are currently pinned to.
+func (c *controller) getPinnedRevisions(ctx context.Context, synth *apiv1.Synthesizer) (map[int64]struct{}, error) {
+	comps := &apiv1.CompositionList{}
+	err := c.client.List(ctx, comps)
+	if err != nil {
+		return nil, fmt.Errorf("listing compositions: %w", err)
+	}
+
+	pinned := map[int64]struct{}{}
+	for _, comp := range comps.Items {
+		ref := comp.Spec.Synthesizer
+		if ref.Name == synth.Name && ref.Revision != nil {
+			pinned[*ref.Revision] = struct{}{}
+		}
+	}
+	return pinned, nil
+}
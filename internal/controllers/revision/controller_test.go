@@ -0,0 +1,74 @@
+package revision
+
+import (
+	"fmt"
+	"testing"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/Azure/eno/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCaptureAndPrune(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	cli := testutil.NewClient(t)
+	c := &controller{client: cli, historyLimit: 2}
+
+	synth := &apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	synth.UID = "test-uid"
+	synth.Generation = 1
+	synth.Spec.Image = "image:1"
+	require.NoError(t, cli.Create(ctx, synth))
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Spec.Synthesizer.Name = synth.Name
+	comp.Spec.Synthesizer.Revision = ptr.To[int64](1)
+	require.NoError(t, cli.Create(ctx, comp))
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(synth)}
+	listGenerations := func() (gens []int64) {
+		list := &apiv1.SynthesizerRevisionList{}
+		require.NoError(t, cli.List(ctx, list))
+		for _, rev := range list.Items {
+			gens = append(gens, rev.Spec.Generation)
+		}
+		return gens
+	}
+
+	for gen := int64(1); gen <= 4; gen++ {
+		synth.Generation = gen
+		synth.Spec.Image = fmt.Sprintf("image:%d", gen)
+		require.NoError(t, cli.Update(ctx, synth))
+
+		// The first pass captures the revision, the second prunes old ones
+		for i := 0; i < 2; i++ {
+			_, err := c.Reconcile(ctx, req)
+			require.NoError(t, err)
+		}
+	}
+
+	// Generation 1 is retained because it's pinned
+	assert.ElementsMatch(t, []int64{1, 3, 4}, listGenerations())
+
+	rev := &apiv1.SynthesizerRevision{}
+	rev.Name = apiv1.SynthesizerRevisionName(synth.Name, 4)
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(rev), rev))
+	assert.Equal(t, "image:4", rev.Spec.SynthesizerSpec.Image)
+	assert.Equal(t, synth.Name, rev.Spec.Synthesizer)
+	require.Len(t, rev.OwnerReferences, 1)
+	assert.Equal(t, synth.UID, rev.OwnerReferences[0].UID)
+
+	// Unpinned revisions can be pruned
+	comp.Spec.Synthesizer.Revision = nil
+	require.NoError(t, cli.Update(ctx, comp))
+	_, err := c.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int64{3, 4}, listGenerations())
+}
@@ -11,7 +11,9 @@ import (
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -41,30 +43,82 @@ const (
 // Rollout order for synthesizer changes is unique to the generation of the synthesizer.
 // Compositions will not receive the new synthesizer in the same order for every generation, but
 // the same generation will always roll out in the same order.
+//
+// Compositions with a higher spec.priority are dispatched first, and a portion of the concurrency limit
+// can be reserved for compositions with a positive priority.
+//
+// Deferred syntheses are also subject to maintenance windows and blackouts, which can be configured
+// cluster-wide and per synthesizer.
 type controller struct {
-	client           client.Client
-	concurrencyLimit int
-	cooldownPeriod   time.Duration
-	cacheGracePeriod time.Duration
+	client                    client.Client
+	concurrencyLimit          int
+	reservedConcurrency       int
+	namespaceConcurrencyLimit int
+	cooldownPeriod            time.Duration
+	cacheGracePeriod          time.Duration
+	schedule                  *schedule
 
 	lastApplied *op
+	lastQuotas  *quotas
+}
+
+type Config struct {
+	// Upper bound on active syntheses across the cluster.
+	ConcurrencyLimit int
+
+	// Number of concurrency slots that can only be used by compositions with a positive priority.
+	ReservedConcurrency int
+
+	// Upper bound on active syntheses of compositions in any one namespace. Zero disables the limit.
+	NamespaceConcurrencyLimit int
+
+	// Minimum period between deferred synthesis operations.
+	CooldownPeriod time.Duration
+
+	// Deferred syntheses are only dispatched while at least one window is open (if any are given)
+	// and none of the blackouts are active.
+	Windows, Blackouts []apiv1.TimeWindow
+
+	// Serve the pending synthesis plan at /debug/scheduler on the metrics server.
+	// Only one controller per manager can enable it.
+	DebugHandler bool
 }
 
-func NewController(mgr ctrl.Manager, concurrencyLimit int, cooldown time.Duration) error {
-	c := &controller{
-		client:           mgr.GetClient(),
-		concurrencyLimit: concurrencyLimit,
-		cooldownPeriod:   cooldown,
-		cacheGracePeriod: time.Second,
+func NewController(mgr ctrl.Manager, config *Config) error {
+	c, err := newController(mgr, config)
+	if err != nil {
+		return err
+	}
+	if config.DebugHandler {
+		if err := manager.AddDebugHandler(mgr, "scheduler", c); err != nil {
+			return fmt.Errorf("adding debug handler: %w", err)
+		}
 	}
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("schedulingController").
 		Watches(&apiv1.Composition{}, manager.SingleEventHandler()).
 		Watches(&apiv1.Synthesizer{}, manager.SingleEventHandler()).
+		Watches(&apiv1.SynthesizerRevision{}, manager.SingleEventHandler()).
 		WithLogConstructor(manager.NewLogConstructor(mgr, "schedulingController")).
 		Complete(c)
 }
 
+func newController(mgr ctrl.Manager, config *Config) (*controller, error) {
+	sched, err := newSchedule(config.Windows, config.Blackouts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rollout schedule: %w", err)
+	}
+	return &controller{
+		client:                    mgr.GetClient(),
+		concurrencyLimit:          config.ConcurrencyLimit,
+		reservedConcurrency:       config.ReservedConcurrency,
+		namespaceConcurrencyLimit: config.NamespaceConcurrencyLimit,
+		cooldownPeriod:            config.CooldownPeriod,
+		cacheGracePeriod:          time.Second,
+		schedule:                  sched,
+	}, nil
+}
+
 func (c *controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := logr.FromContextOrDiscard(ctx)
 	start := time.Now()
@@ -85,50 +139,65 @@ func (c *controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		c.lastApplied = nil
 	}
 
-	synths := &apiv1.SynthesizerList{}
-	err := c.client.List(ctx, synths)
+	snap, err := c.snapshot(ctx)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("listing synthesizers: %w", err)
+		return ctrl.Result{}, err
 	}
-	synthsByName, synthEpoch := indexSynthesizers(synths.Items)
+	now := time.Now()
+	rollouts := newRollouts(snap.synthsByName, snap.comps.Items, now)
+	quotas := newQuotas(snap.synthsByName, snap.comps.Items, c.namespaceConcurrencyLimit)
 
-	comps := &apiv1.CompositionList{}
-	err = c.client.List(ctx, comps)
-	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("listing compositions: %w", err)
+	for _, synth := range snap.synths {
+		r := rollouts[synth.Name]
+		if !r.ShouldRecordHalt(&synth) {
+			continue
+		}
+		reason := r.HaltReason(&synth)
+		if err := c.haltRollout(ctx, &synth, reason); err != nil {
+			return ctrl.Result{}, fmt.Errorf("halting synthesizer rollout: %w", err)
+		}
+		logger.V(0).Info("halted synthesizer rollout", "synthesizerName", synth.Name, "synthesizerGeneration", synth.Generation, "reason", reason)
+		return ctrl.Result{}, nil
 	}
-	nextSlot := c.getNextCooldownSlot(comps)
 
-	var inFlight int
-	var op *op
-	for _, comp := range comps.Items {
-		comp := comp
-		if comp.Synthesizing() {
-			inFlight++
+	for _, synth := range snap.synths {
+		r := rollouts[synth.Name]
+		if r == nil {
+			continue
 		}
-
-		synth, ok := synthsByName[comp.Spec.Synthesizer.Name]
-		if !ok {
+		refs, count, current := r.ApprovalStatus()
+		if current {
 			continue
 		}
-
-		next := newOp(&synth, &comp)
-		if next != nil && (op == nil || next.Less(op)) {
-			op = next
+		if err := c.recordAwaitingApproval(ctx, &synth, refs, count); err != nil {
+			return ctrl.Result{}, fmt.Errorf("updating compositions awaiting approval: %w", err)
 		}
+		logger.V(1).Info("updated compositions awaiting rollout approval", "synthesizerName", synth.Name, "synthesizerGeneration", synth.Generation, "count", count)
+		return ctrl.Result{}, nil
 	}
-	freeSynthesisSlots.Set(float64(c.concurrencyLimit - inFlight))
 
-	if op == nil || inFlight >= c.concurrencyLimit {
+	p := c.plan(logger, snap, rollouts, quotas, now)
+	freeSynthesisSlots.WithLabelValues("global", "").Set(float64(c.concurrencyLimit - p.InFlight))
+	quotas.Report(c.lastQuotas)
+	c.lastQuotas = quotas
+
+	if p.InFlight >= c.concurrencyLimit {
 		return ctrl.Result{}, nil
 	}
-	if wait := time.Until(nextSlot); op.Reason.Deferred() && wait > 0 {
-		return ctrl.Result{RequeueAfter: wait}, nil
+	op := p.Next()
+	if op == nil {
+		if p.Retry.IsZero() {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: time.Until(p.Retry)}, nil
+	}
+	if op.Composition.Spec.Priority <= 0 && p.InFlight >= c.concurrencyLimit-c.reservedConcurrency {
+		return ctrl.Result{}, nil // the remaining slots are reserved for high priority compositions
 	}
-	logger = logger.WithValues("compositionName", op.Composition.Name, "compositionNamespace", op.Composition.Namespace, "reason", op.Reason, "synthEpoch", synthEpoch)
+	logger = logger.WithValues("compositionName", op.Composition.Name, "compositionNamespace", op.Composition.Namespace, "reason", op.Reason, "synthEpoch", snap.synthEpoch)
 
 	// Maintain ordering across synth/composition informers by doing a 2PC on the composition
-	if op.Reason == synthesizerModifiedOp && setSynthEpochAnnotation(op.Composition, synthEpoch) {
+	if op.Reason == synthesizerModifiedOp && setSynthEpochAnnotation(op.Composition, snap.synthEpoch) {
 		if err := c.client.Update(ctx, op.Composition); err != nil {
 			return ctrl.Result{}, fmt.Errorf("updating synthesizer epoch: %w", err)
 		}
@@ -162,6 +231,63 @@ func (c *controller) getNextCooldownSlot(comps *apiv1.CompositionList) time.Time
 	return next.Add(c.cooldownPeriod)
 }
 
+type synthGeneration struct {
+	Name       string
+	Generation int64
+}
+
+// scheduleAllows returns true when the cluster-wide and synthesizer-specific schedules both allow deferred syntheses.
+// Otherwise the returned time is when that might change. Synthesizers with an invalid schedule are blocked.
+func (c *controller) scheduleAllows(logger logr.Logger, cache map[synthGeneration]*schedule, synth *apiv1.Synthesizer, now time.Time) (bool, time.Time) {
+	if ok, reopen := c.schedule.Allowed(now); !ok {
+		return false, reopen
+	}
+	if synth.Spec.Rollout == nil {
+		return true, time.Time{}
+	}
+
+	key := synthGeneration{Name: synth.Name, Generation: synth.Generation}
+	sched, ok := cache[key]
+	if !ok {
+		var err error
+		sched, err = newSchedule(synth.Spec.Rollout.Windows, synth.Spec.Rollout.Blackouts)
+		if err != nil {
+			logger.Error(err, "invalid rollout schedule - deferred syntheses are blocked", "synthesizerName", synth.Name, "synthesizerGeneration", synth.Generation)
+			sched = &schedule{windows: []*timeWindow{{}}} // never open
+		}
+		cache[key] = sched
+	}
+	return sched.Allowed(now)
+}
+
+// earliest returns the earlier of two times, ignoring zero values.
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+func (c *controller) haltRollout(ctx context.Context, synth *apiv1.Synthesizer, reason string) error {
+	copy := synth.DeepCopy()
+	copy.Status.Rollout = &apiv1.RolloutStatus{
+		ObservedGeneration: synth.Generation,
+		Halted:             ptr.To(metav1.Now()),
+		Reason:             reason,
+	}
+	return c.client.Status().Update(ctx, copy)
+}
+
+func (c *controller) recordAwaitingApproval(ctx context.Context, synth *apiv1.Synthesizer, refs []apiv1.CompositionRef, count int) error {
+	copy := synth.DeepCopy()
+	if copy.Status.Rollout == nil || copy.Status.Rollout.ObservedGeneration != synth.Generation {
+		copy.Status.Rollout = &apiv1.RolloutStatus{ObservedGeneration: synth.Generation}
+	}
+	copy.Status.Rollout.AwaitingApproval = refs
+	copy.Status.Rollout.AwaitingApprovalCount = count
+	return c.client.Status().Update(ctx, copy)
+}
+
 func (c *controller) dispatchOp(ctx context.Context, op *op) error {
 	patch, err := json.Marshal(op.BuildPatch())
 	if err != nil {
@@ -181,6 +307,22 @@ func indexSynthesizers(synths []apiv1.Synthesizer) (byName map[string]apiv1.Synt
 	return byName, hex.EncodeToString(h.Sum(nil))
 }
 
+// resolveSynthesizer returns the synthesizer that should be used to synthesize the given composition.
+// Compositions pinned to a particular revision get the synthesizer as it existed at that generation.
+func resolveSynthesizer(synthsByName map[string]apiv1.Synthesizer, revsByName map[string]*apiv1.SynthesizerRevision, comp *apiv1.Composition) (*apiv1.Synthesizer, bool) {
+	ref := comp.Spec.Synthesizer
+	if ref.Revision != nil {
+		rev, ok := revsByName[apiv1.SynthesizerRevisionName(ref.Name, *ref.Revision)]
+		if !ok {
+			return nil, false
+		}
+		return rev.Synthesizer(), true
+	}
+
+	synth, ok := synthsByName[ref.Name]
+	return &synth, ok
+}
+
 func setSynthEpochAnnotation(comp *apiv1.Composition, value string) bool {
 	anno := comp.GetAnnotations()
 	if anno == nil {
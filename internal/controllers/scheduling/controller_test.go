@@ -26,7 +26,7 @@ import (
 func TestBasics(t *testing.T) {
 	ctx := testutil.NewContext(t)
 	mgr := testutil.NewManager(t)
-	require.NoError(t, NewController(mgr.Manager, 100, 2*time.Second))
+	require.NoError(t, NewController(mgr.Manager, &Config{ConcurrencyLimit: 100, CooldownPeriod: 2 * time.Second}))
 	mgr.Start(t)
 	cli := mgr.GetClient()
 
@@ -94,7 +94,7 @@ func TestBasics(t *testing.T) {
 func TestSynthRolloutBasics(t *testing.T) {
 	ctx := testutil.NewContext(t)
 	mgr := testutil.NewManager(t)
-	require.NoError(t, NewController(mgr.Manager, 100, 2*time.Second))
+	require.NoError(t, NewController(mgr.Manager, &Config{ConcurrencyLimit: 100, CooldownPeriod: 2 * time.Second}))
 	mgr.Start(t)
 	cli := mgr.GetClient()
 
@@ -187,7 +187,7 @@ func TestSynthRolloutBasics(t *testing.T) {
 func TestDeferredInput(t *testing.T) {
 	ctx := testutil.NewContext(t)
 	mgr := testutil.NewManager(t)
-	require.NoError(t, NewController(mgr.Manager, 100, 2*time.Second))
+	require.NoError(t, NewController(mgr.Manager, &Config{ConcurrencyLimit: 100, CooldownPeriod: 2 * time.Second}))
 	mgr.Start(t)
 	cli := mgr.GetClient()
 
@@ -253,7 +253,7 @@ func TestDeferredInput(t *testing.T) {
 func TestForcedResynth(t *testing.T) {
 	ctx := testutil.NewContext(t)
 	mgr := testutil.NewManager(t)
-	require.NoError(t, NewController(mgr.Manager, 100, 2*time.Second))
+	require.NoError(t, NewController(mgr.Manager, &Config{ConcurrencyLimit: 100, CooldownPeriod: 2 * time.Second}))
 	mgr.Start(t)
 	cli := mgr.GetClient()
 
@@ -295,7 +295,7 @@ func TestForcedResynth(t *testing.T) {
 func TestChaos(t *testing.T) {
 	t.Run("one leader", func(t *testing.T) {
 		mgr := testutil.NewManager(t)
-		require.NoError(t, NewController(mgr.Manager, 5, time.Second))
+		require.NoError(t, NewController(mgr.Manager, &Config{ConcurrencyLimit: 5, CooldownPeriod: time.Second}))
 		mgr.Start(t)
 
 		testChaos(t, mgr)
@@ -304,8 +304,8 @@ func TestChaos(t *testing.T) {
 	// Run the same test but with another controller competing for the same resources
 	t.Run("zombie leader", func(t *testing.T) {
 		mgr := testutil.NewManager(t)
-		require.NoError(t, NewController(mgr.Manager, 5, time.Second))
-		require.NoError(t, NewController(mgr.Manager, 5, time.Second))
+		require.NoError(t, NewController(mgr.Manager, &Config{ConcurrencyLimit: 5, CooldownPeriod: time.Second}))
+		require.NoError(t, NewController(mgr.Manager, &Config{ConcurrencyLimit: 5, CooldownPeriod: time.Second}))
 		mgr.Start(t)
 
 		testChaos(t, mgr)
@@ -594,3 +594,391 @@ func TestIndexSynthesizersEpoch(t *testing.T) {
 	_, e := indexSynthesizers(synths)
 	assert.NotEqual(t, d, e)
 }
+
+// TestRolloutWaveDispatch proves that synthesizers with a rollout strategy are rolled out in waves.
+func TestRolloutWaveDispatch(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	cli := testutil.NewClient(t)
+	c := &controller{client: cli, concurrencyLimit: 10, cooldownPeriod: time.Hour, cacheGracePeriod: time.Millisecond}
+
+	synth := &apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	synth.Generation = 2
+	synth.Spec.Rollout = &apiv1.RolloutStrategy{
+		Waves: []apiv1.RolloutWave{{Count: 1, BakeTime: &metav1.Duration{Duration: time.Hour}}},
+	}
+	require.NoError(t, cli.Create(ctx, synth))
+
+	var comps []*apiv1.Composition
+	for i := 0; i < 3; i++ {
+		comp := &apiv1.Composition{}
+		comp.Name = fmt.Sprintf("test-comp-%d", i)
+		comp.Namespace = "default"
+		comp.UID = types.UID(comp.Name)
+		comp.Generation = 1
+		comp.Finalizers = []string{"eno.azure.io/cleanup"}
+		comp.Spec.Synthesizer.Name = synth.Name
+		require.NoError(t, cli.Create(ctx, comp))
+
+		comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "foo", ObservedCompositionGeneration: comp.Generation, ObservedSynthesizerGeneration: synth.Generation - 1, Synthesized: ptr.To(metav1.Now())}
+		require.NoError(t, cli.Status().Update(ctx, comp))
+		comps = append(comps, comp)
+	}
+
+	countSynthesizing := func() (n int) {
+		for _, comp := range comps {
+			require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+			if comp.Synthesizing() {
+				n++
+			}
+		}
+		return n
+	}
+
+	// Only the canary is dispatched
+	for i := 0; i < 6; i++ {
+		_, err := c.Reconcile(ctx, ctrl.Request{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, countSynthesizing())
+
+	// The canary is complete but the rest of the rollout is baking
+	var canary *apiv1.Composition
+	for _, comp := range comps {
+		if comp.Synthesizing() {
+			canary = comp
+		}
+	}
+	canary.Status.CurrentSynthesis.Synthesized = ptr.To(metav1.Now())
+	canary.Status.CurrentSynthesis.ObservedSynthesizerGeneration = synth.Generation
+	require.NoError(t, cli.Status().Update(ctx, canary))
+
+	res, err := c.Reconcile(ctx, ctrl.Request{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, countSynthesizing())
+	assert.Greater(t, res.RequeueAfter, time.Minute*59)
+
+	// The final wave is dispatched without waiting for the cooldown period once bake time has elapsed
+	canary.Status.CurrentSynthesis.Synthesized = ptr.To(metav1.NewTime(time.Now().Add(-time.Hour)))
+	require.NoError(t, cli.Status().Update(ctx, canary))
+
+	for i := 0; i < 6; i++ {
+		_, err := c.Reconcile(ctx, ctrl.Request{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, countSynthesizing())
+}
+
+// TestRolloutHalt proves that synthesizer rollouts are halted when too many compositions fail.
+func TestRolloutHalt(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	cli := testutil.NewClient(t)
+	c := &controller{client: cli, concurrencyLimit: 10, cacheGracePeriod: time.Millisecond}
+
+	synth := &apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	synth.Generation = 2
+	synth.Spec.Rollout = &apiv1.RolloutStrategy{FailureThreshold: ptr.To(1)}
+	require.NoError(t, cli.Create(ctx, synth))
+
+	var comps []*apiv1.Composition
+	for i := 0; i < 3; i++ {
+		comp := &apiv1.Composition{}
+		comp.Name = fmt.Sprintf("test-comp-%d", i)
+		comp.Namespace = "default"
+		comp.Generation = 1
+		comp.Finalizers = []string{"eno.azure.io/cleanup"}
+		comp.Spec.Synthesizer.Name = synth.Name
+		require.NoError(t, cli.Create(ctx, comp))
+
+		comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "foo", ObservedCompositionGeneration: comp.Generation, ObservedSynthesizerGeneration: synth.Generation - 1, Synthesized: ptr.To(metav1.Now())}
+		require.NoError(t, cli.Status().Update(ctx, comp))
+		comps = append(comps, comp)
+	}
+
+	// One composition has failed using the new synthesizer
+	comps[0].Status.CurrentSynthesis.ObservedSynthesizerGeneration = synth.Generation
+	comps[0].Status.CurrentSynthesis.Results = []apiv1.Result{{Severity: "error", Message: "boom"}}
+	require.NoError(t, cli.Status().Update(ctx, comps[0]))
+
+	for i := 0; i < 6; i++ {
+		_, err := c.Reconcile(ctx, ctrl.Request{})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(synth), synth))
+	require.NotNil(t, synth.Status.Rollout)
+	assert.True(t, synth.RolloutHalted())
+	assert.Equal(t, "1 composition(s) failed after receiving generation 2 (e.g. default/test-comp-0)", synth.Status.Rollout.Reason)
+
+	for _, comp := range comps {
+		require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+		assert.False(t, comp.Synthesizing(), comp.Name)
+	}
+}
+
+// TestPinnedRevision proves that compositions pinned to a synthesizer revision are synthesized using that revision,
+// and don't receive later synthesizer changes.
+func TestPinnedRevision(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	cli := testutil.NewClient(t)
+	c := &controller{client: cli, concurrencyLimit: 10, cacheGracePeriod: time.Millisecond}
+
+	synth := &apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	synth.Generation = 3
+	require.NoError(t, cli.Create(ctx, synth))
+
+	rev := &apiv1.SynthesizerRevision{}
+	rev.Name = apiv1.SynthesizerRevisionName(synth.Name, 1)
+	rev.Spec.Synthesizer = synth.Name
+	rev.Spec.Generation = 1
+	require.NoError(t, cli.Create(ctx, rev))
+
+	newComp := func(name string, revision *int64, compGen int64) *apiv1.Composition {
+		comp := &apiv1.Composition{}
+		comp.Name = name
+		comp.Namespace = "default"
+		comp.Generation = 2
+		comp.Finalizers = []string{"eno.azure.io/cleanup"}
+		comp.Spec.Synthesizer.Name = synth.Name
+		comp.Spec.Synthesizer.Revision = revision
+		require.NoError(t, cli.Create(ctx, comp))
+
+		comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "foo", ObservedCompositionGeneration: compGen, ObservedSynthesizerGeneration: 1, Synthesized: ptr.To(metav1.Now())}
+		require.NoError(t, cli.Status().Update(ctx, comp))
+		return comp
+	}
+	unpinned := newComp("unpinned", nil, 2)
+	pinned := newComp("pinned", ptr.To[int64](1), 2)
+	newlyPinned := newComp("newly-pinned", ptr.To[int64](1), 1)
+	missingRevision := newComp("missing-revision", ptr.To[int64](2), 1)
+
+	for i := 0; i < 10; i++ {
+		_, err := c.Reconcile(ctx, ctrl.Request{})
+		require.NoError(t, err)
+	}
+
+	for _, comp := range []*apiv1.Composition{unpinned, pinned, newlyPinned, missingRevision} {
+		require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	}
+	assert.True(t, unpinned.Synthesizing(), "receives the new synthesizer")
+	assert.False(t, pinned.Synthesizing(), "already synthesized using the pinned revision")
+	assert.True(t, newlyPinned.Synthesizing(), "resynthesized when pinned")
+	assert.False(t, missingRevision.Synthesizing(), "can't be synthesized without the revision")
+}
+
+// TestReservedConcurrency proves that reserved synthesis slots are only used by compositions with a positive priority.
+func TestReservedConcurrency(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	cli := testutil.NewClient(t)
+	c := &controller{client: cli, concurrencyLimit: 2, reservedConcurrency: 1, cacheGracePeriod: time.Millisecond}
+
+	synth := &apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	require.NoError(t, cli.Create(ctx, synth))
+
+	newComp := func(name string, priority int32) *apiv1.Composition {
+		comp := &apiv1.Composition{}
+		comp.Name = name
+		comp.Namespace = "default"
+		comp.Finalizers = []string{"eno.azure.io/cleanup"}
+		comp.Spec.Synthesizer.Name = synth.Name
+		comp.Generation = 2
+		comp.Spec.Priority = priority
+		require.NoError(t, cli.Create(ctx, comp))
+
+		comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "foo", ObservedCompositionGeneration: 1, Synthesized: ptr.To(metav1.Now())}
+		require.NoError(t, cli.Status().Update(ctx, comp))
+		return comp
+	}
+	low1 := newComp("low-1", 0)
+	low2 := newComp("low-2", 0)
+
+	reconcile := func() {
+		for i := 0; i < 5; i++ {
+			_, err := c.Reconcile(ctx, ctrl.Request{})
+			require.NoError(t, err)
+		}
+	}
+	synthesizing := func(comp *apiv1.Composition) bool {
+		require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+		return comp.Synthesizing()
+	}
+
+	// Only one of the low priority compositions can be dispatched
+	reconcile()
+	assert.NotEqual(t, synthesizing(low1), synthesizing(low2))
+
+	// The reserved slot is available to high priority compositions
+	high := newComp("high", 1)
+	reconcile()
+	assert.True(t, synthesizing(high))
+}
+
+// TestSynthesizerConcurrencyLimit proves that synthesizers can limit the number of concurrent syntheses of their compositions.
+func TestSynthesizerConcurrencyLimit(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	cli := testutil.NewClient(t)
+	c := &controller{client: cli, concurrencyLimit: 10, cacheGracePeriod: time.Millisecond}
+
+	synth := &apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	synth.Spec.ConcurrencyLimit = ptr.To(1)
+	require.NoError(t, cli.Create(ctx, synth))
+
+	var comps []*apiv1.Composition
+	for i := 0; i < 3; i++ {
+		comp := &apiv1.Composition{}
+		comp.Name = fmt.Sprintf("test-comp-%d", i)
+		comp.Namespace = "default"
+		comp.Generation = 2
+		comp.Finalizers = []string{"eno.azure.io/cleanup"}
+		comp.Spec.Synthesizer.Name = synth.Name
+		require.NoError(t, cli.Create(ctx, comp))
+
+		comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "foo", ObservedCompositionGeneration: 1, Synthesized: ptr.To(metav1.Now())}
+		require.NoError(t, cli.Status().Update(ctx, comp))
+		comps = append(comps, comp)
+	}
+
+	for i := 0; i < 5; i++ {
+		_, err := c.Reconcile(ctx, ctrl.Request{})
+		require.NoError(t, err)
+	}
+
+	var n int
+	for _, comp := range comps {
+		require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+		if comp.Synthesizing() {
+			n++
+		}
+	}
+	assert.Equal(t, 1, n)
+}
+
+// TestMaintenanceWindows proves that deferred syntheses are blocked outside of maintenance windows while composition changes are not.
+func TestMaintenanceWindows(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	cli := testutil.NewClient(t)
+	c := &controller{client: cli, concurrencyLimit: 10, cacheGracePeriod: time.Millisecond}
+
+	now := time.Now()
+	synth := &apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	synth.Generation = 2
+	synth.Spec.Rollout = &apiv1.RolloutStrategy{
+		Blackouts: []apiv1.TimeWindow{{Start: &metav1.Time{Time: now.Add(-time.Hour)}, End: &metav1.Time{Time: now.Add(time.Hour)}}},
+	}
+	require.NoError(t, cli.Create(ctx, synth))
+
+	newComp := func(name string, compGen int64) *apiv1.Composition {
+		comp := &apiv1.Composition{}
+		comp.Name = name
+		comp.Namespace = "default"
+		comp.Generation = 2
+		comp.Finalizers = []string{"eno.azure.io/cleanup"}
+		comp.Spec.Synthesizer.Name = synth.Name
+		require.NoError(t, cli.Create(ctx, comp))
+
+		comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "foo", ObservedCompositionGeneration: compGen, ObservedSynthesizerGeneration: 1, Synthesized: ptr.To(metav1.Now())}
+		require.NoError(t, cli.Status().Update(ctx, comp))
+		return comp
+	}
+	rollout := newComp("rollout", 2)
+	modified := newComp("modified", 1)
+
+	var res ctrl.Result
+	for i := 0; i < 5; i++ {
+		var err error
+		res, err = c.Reconcile(ctx, ctrl.Request{})
+		require.NoError(t, err)
+	}
+
+	for _, comp := range []*apiv1.Composition{rollout, modified} {
+		require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	}
+	assert.False(t, rollout.Synthesizing(), "blocked by the synthesizer's blackout")
+	assert.True(t, modified.Synthesizing(), "composition changes aren't subject to blackouts")
+	assert.InDelta(t, time.Hour, res.RequeueAfter, float64(time.Minute), "retried when the blackout ends")
+
+	// Cluster-wide windows also apply
+	synth.Spec.Rollout = nil
+	require.NoError(t, cli.Update(ctx, synth))
+	c.schedule, _ = newSchedule([]apiv1.TimeWindow{{Start: &metav1.Time{Time: now.Add(time.Hour * 2)}, End: &metav1.Time{Time: now.Add(time.Hour * 3)}}}, nil)
+
+	res, err := c.Reconcile(ctx, ctrl.Request{})
+	require.NoError(t, err)
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(rollout), rollout))
+	assert.False(t, rollout.Synthesizing(), "outside of the cluster's window")
+	assert.InDelta(t, time.Hour*2, res.RequeueAfter, float64(time.Minute), "retried when the window opens")
+
+	c.schedule = nil
+	for i := 0; i < 3; i++ {
+		_, err = c.Reconcile(ctx, ctrl.Request{})
+		require.NoError(t, err)
+	}
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(rollout), rollout))
+	assert.True(t, rollout.Synthesizing())
+}
+
+// TestRolloutApprovalDispatch proves that synthesizer rollouts requiring approval are only dispatched once approved.
+func TestRolloutApprovalDispatch(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	cli := testutil.NewClient(t)
+	c := &controller{client: cli, concurrencyLimit: 10, cacheGracePeriod: time.Millisecond}
+
+	synth := &apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	synth.Generation = 2
+	synth.Spec.Rollout = &apiv1.RolloutStrategy{RequireApproval: true}
+	require.NoError(t, cli.Create(ctx, synth))
+
+	var comps []*apiv1.Composition
+	for i := 0; i < 2; i++ {
+		comp := &apiv1.Composition{}
+		comp.Name = fmt.Sprintf("test-comp-%d", i)
+		comp.Namespace = "default"
+		comp.Generation = 1
+		comp.Finalizers = []string{"eno.azure.io/cleanup"}
+		comp.Spec.Synthesizer.Name = synth.Name
+		require.NoError(t, cli.Create(ctx, comp))
+
+		comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "foo", ObservedCompositionGeneration: comp.Generation, ObservedSynthesizerGeneration: 1, Synthesized: ptr.To(metav1.Now())}
+		require.NoError(t, cli.Status().Update(ctx, comp))
+		comps = append(comps, comp)
+	}
+
+	reconcile := func() {
+		for i := 0; i < 5; i++ {
+			_, err := c.Reconcile(ctx, ctrl.Request{})
+			require.NoError(t, err)
+		}
+	}
+	synthesizing := func() (n int) {
+		for _, comp := range comps {
+			require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+			if comp.Synthesizing() {
+				n++
+			}
+		}
+		return n
+	}
+
+	reconcile()
+	assert.Equal(t, 0, synthesizing())
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(synth), synth))
+	require.NotNil(t, synth.Status.Rollout)
+	assert.Equal(t, 2, synth.Status.Rollout.AwaitingApprovalCount)
+	assert.Len(t, synth.Status.Rollout.AwaitingApproval, 2)
+
+	// Approve the rollout
+	synth.Annotations = map[string]string{"eno.azure.io/approved-rollout": "2"}
+	require.NoError(t, cli.Update(ctx, synth))
+
+	reconcile()
+	assert.Equal(t, 2, synthesizing())
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(synth), synth))
+	assert.Zero(t, synth.Status.Rollout.AwaitingApprovalCount)
+	assert.Empty(t, synth.Status.Rollout.AwaitingApproval)
+}
@@ -0,0 +1,144 @@
+package scheduling
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a standard 5-field cron expression (minute hour day-of-month month day-of-week).
+// Fields support "*", single values, ranges ("1-5"), steps ("*/15", "0-30/10"), and comma-separated lists.
+// Day-of-week is 0-7 where both 0 and 7 are Sunday. Like cron, when both day fields are restricted
+// a time matches if either of them does.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64 // bitsets
+	domStar, dowStar              bool
+}
+
+// cronSearchLimit bounds the search for the next/previous activation of schedules that rarely (or never) match e.g. Feb 30.
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	s := &cronSchedule{domStar: fields[2] == "*", dowStar: fields[4] == "*"}
+	var err error
+	for _, f := range []struct {
+		dest     *uint64
+		min, max int
+		name     string
+	}{
+		{&s.minute, 0, 59, "minute"},
+		{&s.hour, 0, 23, "hour"},
+		{&s.dom, 1, 31, "day of month"},
+		{&s.month, 1, 12, "month"},
+		{&s.dow, 0, 7, "day of week"},
+	} {
+		*f.dest, err = parseCronField(fields[0], f.min, f.max)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", f.name, err)
+		}
+		fields = fields[1:]
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1 // 7 is an alias of Sunday
+	}
+	return s, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			loStr, hiStr, isRange := strings.Cut(rng, "-")
+			var err error
+			lo, err = strconv.Atoi(loStr)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", loStr)
+			}
+			hi = lo
+			if isRange {
+				hi, err = strconv.Atoi(hiStr)
+				if err != nil {
+					return 0, fmt.Errorf("invalid value %q", hiStr)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range [%d, %d]", part, min, max)
+		}
+
+		for i := lo; i <= hi; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first activation strictly after the given time, or the zero time if none was found.
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Prev returns the last activation at or before the given time, or the zero time if none was found.
+func (s *cronSchedule) Prev(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute)
+	limit := t.Add(-cronSearchLimit)
+	for t.After(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).Add(-time.Minute)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Add(-time.Minute)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(-time.Minute)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(-time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,74 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	for _, expr := range []string{"* * * * *", "*/15 0-6 1,15 * 1-5", "0 22 * * 7", "30 2 29 2 *", "0-30/10 * * 1-12/3 *"} {
+		_, err := parseCron(expr)
+		assert.NoError(t, err, expr)
+	}
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := parseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCronNextPrev(t *testing.T) {
+	tests := []struct {
+		Name, Expr       string
+		Time, Next, Prev string
+	}{
+		{
+			Name: "every minute",
+			Expr: "* * * * *",
+			Time: "2024-03-10T12:30:15Z", Next: "2024-03-10T12:31:00Z", Prev: "2024-03-10T12:30:00Z",
+		},
+		{
+			Name: "friday night",
+			Expr: "0 22 * * 5",
+			Time: "2024-03-10T12:30:00Z", Next: "2024-03-15T22:00:00Z", Prev: "2024-03-08T22:00:00Z",
+		},
+		{
+			Name: "sunday as 7",
+			Expr: "0 0 * * 7",
+			Time: "2024-03-12T00:00:00Z", Next: "2024-03-17T00:00:00Z", Prev: "2024-03-10T00:00:00Z",
+		},
+		{
+			Name: "either day field matches",
+			Expr: "0 0 1 * 1",
+			Time: "2024-03-02T00:00:00Z", Next: "2024-03-04T00:00:00Z", Prev: "2024-03-01T00:00:00Z",
+		},
+		{
+			Name: "leap day",
+			Expr: "0 0 29 2 *",
+			Time: "2024-03-01T00:00:00Z", Next: "2028-02-29T00:00:00Z", Prev: "2024-02-29T00:00:00Z",
+		},
+		{
+			Name: "step",
+			Expr: "*/20 9-17 * * *",
+			Time: "2024-03-10T17:45:00Z", Next: "2024-03-11T09:00:00Z", Prev: "2024-03-10T17:40:00Z",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			s, err := parseCron(tc.Expr)
+			require.NoError(t, err)
+
+			now, _ := time.Parse(time.RFC3339, tc.Time)
+			assert.Equal(t, tc.Next, s.Next(now).Format(time.RFC3339))
+			assert.Equal(t, tc.Prev, s.Prev(now).Format(time.RFC3339))
+		})
+	}
+
+	// No matches
+	s, err := parseCron("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, s.Next(time.Now()).IsZero())
+	assert.True(t, s.Prev(time.Now()).IsZero())
+}
@@ -0,0 +1,95 @@
+package scheduling
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// debugState is the scheduler's view of pending syntheses, served by the debug endpoint.
+type debugState struct {
+	Time                time.Time   `json:"time"`
+	InFlight            int         `json:"inFlight"`
+	ConcurrencyLimit    int         `json:"concurrencyLimit"`
+	ReservedConcurrency int         `json:"reservedConcurrency"`
+	NextCooldownSlot    time.Time   `json:"nextCooldownSlot"`
+	Ops                 []debugOp   `json:"ops"`     // in dispatch order
+	Skipped             []debugSkip `json:"skipped"` // sorted by namespace/name
+}
+
+type debugOp struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Synthesizer string `json:"synthesizer"`
+	Priority    int32  `json:"priority,omitempty"`
+	Reason      string `json:"reason"`
+}
+
+type debugSkip struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	OpReason  string `json:"opReason,omitempty"` // empty when the composition has no pending op
+	Reason    string `json:"reason"`
+}
+
+// ServeHTTP exposes the current scheduling plan as JSON to help debug compositions that aren't being synthesized.
+// It doesn't modify any state.
+func (c *controller) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logr.FromContextOrDiscard(ctx)
+	snap, err := c.snapshot(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	rollouts := newRollouts(snap.synthsByName, snap.comps.Items, now)
+	quotas := newQuotas(snap.synthsByName, snap.comps.Items, c.namespaceConcurrencyLimit)
+	p := c.plan(logger, snap, rollouts, quotas, now)
+
+	state := &debugState{
+		Time:                now,
+		InFlight:            p.InFlight,
+		ConcurrencyLimit:    c.concurrencyLimit,
+		ReservedConcurrency: c.reservedConcurrency,
+		NextCooldownSlot:    p.NextCooldownSlot,
+		Ops:                 []debugOp{},
+		Skipped:             []debugSkip{},
+	}
+
+	sort.Slice(p.Ops, func(i, j int) bool { return p.Ops[i].Less(p.Ops[j]) })
+	for _, op := range p.Ops {
+		state.Ops = append(state.Ops, debugOp{
+			Name:        op.Composition.Name,
+			Namespace:   op.Composition.Namespace,
+			Synthesizer: op.Synthesizer.Name,
+			Priority:    op.Composition.Spec.Priority,
+			Reason:      op.Reason.String(),
+		})
+	}
+
+	for _, s := range p.Skipped {
+		ds := debugSkip{Name: s.Composition.Name, Namespace: s.Composition.Namespace, Reason: s.Reason}
+		if s.Op != nil {
+			ds.OpReason = s.Op.Reason.String()
+		}
+		state.Skipped = append(state.Skipped, ds)
+	}
+	sort.Slice(state.Skipped, func(i, j int) bool {
+		if state.Skipped[i].Namespace != state.Skipped[j].Namespace {
+			return state.Skipped[i].Namespace < state.Skipped[j].Namespace
+		}
+		return state.Skipped[i].Name < state.Skipped[j].Name
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(state); err != nil {
+		logger.Error(err, "writing debug response")
+	}
+}
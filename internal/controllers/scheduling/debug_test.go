@@ -0,0 +1,69 @@
+package scheduling
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/Azure/eno/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestDebugHandler(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	cli := testutil.NewClient(t)
+	c := &controller{client: cli, concurrencyLimit: 10, cooldownPeriod: time.Hour}
+
+	synth := &apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	synth.Generation = 2
+	require.NoError(t, cli.Create(ctx, synth))
+
+	newComp := func(name, synth string, priority int32, finalizer bool, syn *apiv1.Synthesis) {
+		comp := &apiv1.Composition{}
+		comp.Name = name
+		comp.Namespace = "default"
+		comp.Generation = 2
+		comp.Spec.Synthesizer.Name = synth
+		comp.Spec.Priority = priority
+		if finalizer {
+			comp.Finalizers = []string{"eno.azure.io/cleanup"}
+		}
+		require.NoError(t, cli.Create(ctx, comp))
+
+		comp.Status.CurrentSynthesis = syn
+		require.NoError(t, cli.Status().Update(ctx, comp))
+	}
+	newComp("low", synth.Name, 0, true, nil)
+	newComp("high", synth.Name, 1, true, nil)
+	newComp("no-finalizer", synth.Name, 0, false, nil)
+	newComp("missing-synth", "nope", 0, true, nil)
+	newComp("recently-deferred", synth.Name, 0, true, &apiv1.Synthesis{UUID: "foo", ObservedCompositionGeneration: 2, ObservedSynthesizerGeneration: 2, Deferred: true, Initialized: ptr.To(metav1.Now()), Synthesized: ptr.To(metav1.Now())})
+	newComp("cooldown", synth.Name, 0, true, &apiv1.Synthesis{UUID: "foo", ObservedCompositionGeneration: 2, ObservedSynthesizerGeneration: 1, Synthesized: ptr.To(metav1.Now())})
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/scheduler", nil).WithContext(ctx))
+	require.Equal(t, 200, rec.Code)
+
+	state := &debugState{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), state))
+	assert.Equal(t, 0, state.InFlight)
+	assert.Equal(t, 10, state.ConcurrencyLimit)
+	assert.False(t, state.NextCooldownSlot.IsZero())
+
+	assert.Equal(t, []debugOp{
+		{Name: "high", Namespace: "default", Synthesizer: synth.Name, Priority: 1, Reason: "InitialSynthesis"},
+		{Name: "low", Namespace: "default", Synthesizer: synth.Name, Reason: "InitialSynthesis"},
+	}, state.Ops)
+
+	assert.Equal(t, []debugSkip{
+		{Name: "cooldown", Namespace: "default", OpReason: "SynthesizerModified", Reason: "Cooldown"},
+		{Name: "missing-synth", Namespace: "default", Reason: "SynthesizerNotFound"},
+		{Name: "no-finalizer", Namespace: "default", Reason: "MissingFinalizer"},
+	}, state.Skipped)
+}
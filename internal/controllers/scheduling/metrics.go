@@ -6,11 +6,11 @@ import (
 )
 
 var (
-	freeSynthesisSlots = prometheus.NewGauge(
+	freeSynthesisSlots = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "eno_free_synthesis_slots",
-			Help: "Count of how many syntheses could be dispatched concurrently",
-		},
+			Help: "Count of how many syntheses could be dispatched concurrently, broken out by the scope of the limit (global, synthesizer, or namespace)",
+		}, []string{"scope", "name"},
 	)
 
 	schedulingLatency = prometheus.NewHistogram(
@@ -3,8 +3,6 @@ package scheduling
 import (
 	"bytes"
 	"context"
-	"fmt"
-	"hash/fnv"
 	"reflect"
 	"time"
 
@@ -78,7 +76,34 @@ func classifyOp(synth *apiv1.Synthesizer, comp *apiv1.Composition, syn *apiv1.Sy
 	return 0, false
 }
 
+// skipReason explains why newOp didn't return an op for the given composition.
+// An empty string is returned for compositions that are simply up to date.
+func skipReason(synth *apiv1.Synthesizer, comp *apiv1.Composition) string {
+	switch {
+	case comp.DeletionTimestamp != nil:
+		return "Deleting"
+	case !comp.InputsExist(synth):
+		return "MissingInputs"
+	case comp.InputsOutOfLockstep(synth):
+		return "InputsOutOfLockstep"
+	case !controllerutil.ContainsFinalizer(comp, "eno.azure.io/cleanup"):
+		return "MissingFinalizer"
+	}
+
+	if reason, ok := classifyOp(synth, comp, comp.Status.CurrentSynthesis); ok && reason.Deferred() && comp.Synthesizing() {
+		return "WaitingForInFlightSynthesis" // deferred ops don't replace in-flight syntheses
+	}
+	if comp.ShouldIgnoreSideEffects() {
+		return "IgnoringSideEffects"
+	}
+	return ""
+}
+
 func (o *op) Less(than *op) bool {
+	if o.Composition.Spec.Priority != than.Composition.Spec.Priority {
+		return o.Composition.Spec.Priority > than.Composition.Spec.Priority
+	}
+
 	if o.Reason == synthesizerModifiedOp && than.Reason == synthesizerModifiedOp {
 		cmp := bytes.Compare(o.SynthRolloutOrderHash(), than.SynthRolloutOrderHash())
 		if cmp != 0 {
@@ -97,9 +122,7 @@ func (o *op) Less(than *op) bool {
 // This mechanism maintains determinism while shuffling the rollout order of every synthesizer change.
 func (o *op) SynthRolloutOrderHash() []byte {
 	if o.synthRolloutHash == nil {
-		hash := fnv.New64()
-		fmt.Fprintf(hash, "%s:%d:%s", o.Synthesizer.UID, o.Synthesizer.Generation, o.Composition.UID)
-		o.synthRolloutHash = hash.Sum(nil)
+		o.synthRolloutHash = rolloutOrderHash(o.Synthesizer, o.Composition)
 	}
 	return o.synthRolloutHash
 }
@@ -397,3 +397,30 @@ func TestOpPriorityTies(t *testing.T) {
 		}
 	}
 }
+
+func TestOpCompositionPriority(t *testing.T) {
+	synth := &apiv1.Synthesizer{ObjectMeta: metav1.ObjectMeta{UID: "synth", Generation: 1}}
+	newOp := func(uid string, priority int32, reason opReason) *op {
+		comp := &apiv1.Composition{ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid)}}
+		comp.Spec.Priority = priority
+		return &op{Composition: comp, Synthesizer: synth, Reason: reason}
+	}
+	ops := []*op{
+		newOp("low-initial", -1, initialSynthesisOp),
+		newOp("default-initial", 0, initialSynthesisOp),
+		newOp("default-synth", 0, synthesizerModifiedOp),
+		newOp("high-synth", 10, synthesizerModifiedOp),
+		newOp("high-input", 10, inputModifiedOp),
+	}
+
+	for i := 0; i < 100; i++ {
+		rand.Shuffle(len(ops), func(i, j int) { ops[i], ops[j] = ops[j], ops[i] })
+		sort.Slice(ops, func(i, j int) bool { return ops[i].Less(ops[j]) })
+
+		var names []string
+		for _, op := range ops {
+			names = append(names, string(op.Composition.UID))
+		}
+		assert.Equal(t, []string{"high-input", "high-synth", "default-initial", "default-synth", "low-initial"}, names, "pass: %d", i)
+	}
+}
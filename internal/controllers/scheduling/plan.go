@@ -0,0 +1,153 @@
+package scheduling
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+)
+
+// snapshot holds the resources considered by a single scheduling pass.
+type snapshot struct {
+	synths       []apiv1.Synthesizer
+	synthsByName map[string]apiv1.Synthesizer
+	synthEpoch   string
+	revsByName   map[string]*apiv1.SynthesizerRevision
+	comps        *apiv1.CompositionList
+}
+
+func (c *controller) snapshot(ctx context.Context) (*snapshot, error) {
+	synths := &apiv1.SynthesizerList{}
+	err := c.client.List(ctx, synths)
+	if err != nil {
+		return nil, fmt.Errorf("listing synthesizers: %w", err)
+	}
+	s := &snapshot{synths: synths.Items}
+	s.synthsByName, s.synthEpoch = indexSynthesizers(synths.Items)
+
+	revs := &apiv1.SynthesizerRevisionList{}
+	err = c.client.List(ctx, revs)
+	if err != nil {
+		return nil, fmt.Errorf("listing synthesizer revisions: %w", err)
+	}
+	s.revsByName = map[string]*apiv1.SynthesizerRevision{}
+	for i := range revs.Items {
+		s.revsByName[revs.Items[i].Name] = &revs.Items[i]
+	}
+
+	s.comps = &apiv1.CompositionList{}
+	err = c.client.List(ctx, s.comps)
+	if err != nil {
+		return nil, fmt.Errorf("listing compositions: %w", err)
+	}
+	return s, nil
+}
+
+// plan is the outcome of a scheduling pass: every op that could be dispatched, and why other compositions were skipped.
+// Ops are still subject to the concurrency limit.
+type plan struct {
+	Ops              []*op // unordered
+	Skipped          []*skip
+	InFlight         int
+	NextCooldownSlot time.Time
+	Retry            time.Time // earliest time at which a blocked op might become dispatchable
+}
+
+// skip explains why a composition that may need synthesis isn't currently eligible for it.
+type skip struct {
+	Composition *apiv1.Composition
+	Op          *op // nil when blocked before an op could be created
+	Reason      string
+}
+
+func (c *controller) plan(logger logr.Logger, snap *snapshot, rollouts map[string]*rollout, quotas *quotas, now time.Time) *plan {
+	p := &plan{NextCooldownSlot: c.getNextCooldownSlot(snap.comps)}
+	for _, r := range rollouts {
+		p.Retry = earliest(p.Retry, r.nextHealthCheck)
+	}
+
+	schedules := map[synthGeneration]*schedule{}
+	for i := range snap.comps.Items {
+		comp := &snap.comps.Items[i]
+		if comp.Synthesizing() {
+			p.InFlight++
+		}
+
+		synth, ok := resolveSynthesizer(snap.synthsByName, snap.revsByName, comp)
+		if !ok {
+			p.skip(comp, nil, "SynthesizerNotFound")
+			continue
+		}
+
+		next := newOp(synth, comp)
+		if next == nil {
+			if reason := skipReason(synth, comp); reason != "" {
+				p.skip(comp, nil, reason)
+			}
+			continue
+		}
+
+		var r *rollout
+		if comp.Spec.Synthesizer.Revision == nil {
+			r = rollouts[synth.Name] // pinned compositions don't participate in rollouts
+		}
+		if next.Reason == synthesizerModifiedOp && r.Halted() {
+			p.skip(comp, next, "RolloutHalted") // halted rollouts resume when the synthesizer is modified
+			continue
+		}
+
+		if next.Reason.Deferred() {
+			allowed, reopen := c.scheduleAllows(logger, schedules, synth, now)
+			if !allowed {
+				p.Retry = earliest(p.Retry, reopen)
+				p.skip(comp, next, "OutsideMaintenanceWindow")
+				continue
+			}
+		}
+
+		// Synthesizers with a rollout strategy are gated by waves instead of the global cooldown
+		if next.Reason == synthesizerModifiedOp && r.HasWaves() {
+			allowed, nextWave := r.Allowed(comp)
+			if !allowed {
+				p.Retry = earliest(p.Retry, nextWave)
+				p.skip(comp, next, "WaitingForRolloutWave")
+				continue
+			}
+		} else if next.Reason.Deferred() && now.Before(p.NextCooldownSlot) {
+			p.Retry = earliest(p.Retry, p.NextCooldownSlot)
+			p.skip(comp, next, "Cooldown")
+			continue
+		}
+
+		if next.Reason == synthesizerModifiedOp && !r.Approved(comp) {
+			p.skip(comp, next, "AwaitingApproval")
+			continue
+		}
+
+		if !quotas.Allowed(comp) {
+			p.skip(comp, next, "QuotaExceeded")
+			continue
+		}
+
+		p.Ops = append(p.Ops, next)
+	}
+	return p
+}
+
+func (p *plan) skip(comp *apiv1.Composition, op *op, reason string) {
+	p.Skipped = append(p.Skipped, &skip{Composition: comp, Op: op, Reason: reason})
+}
+
+// Next returns the op that should be dispatched first, or nil if there aren't any.
+func (p *plan) Next() *op {
+	var next *op
+	for _, op := range p.Ops {
+		if next == nil || op.Less(next) {
+			next = op
+		}
+	}
+	return next
+}
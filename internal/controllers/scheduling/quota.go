@@ -0,0 +1,79 @@
+package scheduling
+
+import (
+	apiv1 "github.com/Azure/eno/api/v1"
+)
+
+// quotas enforce per-synthesizer and per-namespace limits on the number of in-flight syntheses.
+// The cluster-wide concurrency limit is enforced separately.
+type quotas struct {
+	namespaceLimit int            // zero when unlimited
+	synthLimits    map[string]int // only synthesizers with a limit
+
+	bySynth     map[string]int
+	byNamespace map[string]int
+}
+
+func newQuotas(synthsByName map[string]apiv1.Synthesizer, comps []apiv1.Composition, namespaceLimit int) *quotas {
+	q := &quotas{
+		namespaceLimit: namespaceLimit,
+		synthLimits:    map[string]int{},
+		bySynth:        map[string]int{},
+		byNamespace:    map[string]int{},
+	}
+	for name, synth := range synthsByName {
+		if synth.Spec.ConcurrencyLimit != nil {
+			q.synthLimits[name] = *synth.Spec.ConcurrencyLimit
+		}
+	}
+	for _, comp := range comps {
+		if namespaceLimit > 0 {
+			q.byNamespace[comp.Namespace] += 0 // report every namespace, even without in-flight syntheses
+		}
+		if comp.Synthesizing() {
+			q.bySynth[comp.Spec.Synthesizer.Name]++
+			q.byNamespace[comp.Namespace]++
+		}
+	}
+	return q
+}
+
+// Allowed returns true when dispatching a synthesis for the given composition would not exceed any quota.
+func (q *quotas) Allowed(comp *apiv1.Composition) bool {
+	if comp.Synthesizing() {
+		return true // replacing an in-flight synthesis doesn't consume another slot
+	}
+	if limit, ok := q.synthLimits[comp.Spec.Synthesizer.Name]; ok && q.bySynth[comp.Spec.Synthesizer.Name] >= limit {
+		return false
+	}
+	if q.namespaceLimit > 0 && q.byNamespace[comp.Namespace] >= q.namespaceLimit {
+		return false
+	}
+	return true
+}
+
+// Report updates the free synthesis slots metric for every scoped quota.
+// Series reported by prev that no longer exist are removed rather than resetting the whole metric,
+// which would briefly hide every series from scrapes.
+func (q *quotas) Report(prev *quotas) {
+	if prev != nil {
+		for name := range prev.synthLimits {
+			if _, ok := q.synthLimits[name]; !ok {
+				freeSynthesisSlots.DeleteLabelValues("synthesizer", name)
+			}
+		}
+		for ns := range prev.byNamespace {
+			if _, ok := q.byNamespace[ns]; !ok || q.namespaceLimit == 0 {
+				freeSynthesisSlots.DeleteLabelValues("namespace", ns)
+			}
+		}
+	}
+	for name, limit := range q.synthLimits {
+		freeSynthesisSlots.WithLabelValues("synthesizer", name).Set(float64(limit - q.bySynth[name]))
+	}
+	if q.namespaceLimit > 0 {
+		for ns, n := range q.byNamespace {
+			freeSynthesisSlots.WithLabelValues("namespace", ns).Set(float64(q.namespaceLimit - n))
+		}
+	}
+}
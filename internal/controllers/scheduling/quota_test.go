@@ -0,0 +1,72 @@
+package scheduling
+
+import (
+	"testing"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+)
+
+func TestQuotas(t *testing.T) {
+	limited := apiv1.Synthesizer{}
+	limited.Name = "limited"
+	limited.Spec.ConcurrencyLimit = ptr.To(1)
+
+	unlimited := apiv1.Synthesizer{}
+	unlimited.Name = "unlimited"
+
+	synths := map[string]apiv1.Synthesizer{limited.Name: limited, unlimited.Name: unlimited}
+
+	newComp := func(ns, synth string, synthesizing bool) apiv1.Composition {
+		comp := apiv1.Composition{}
+		comp.Namespace = ns
+		comp.Spec.Synthesizer.Name = synth
+		if synthesizing {
+			comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "foo"}
+		}
+		return comp
+	}
+	comps := []apiv1.Composition{
+		newComp("ns-1", limited.Name, true),
+		newComp("ns-1", unlimited.Name, true),
+		newComp("ns-2", unlimited.Name, false),
+	}
+
+	q := newQuotas(synths, comps, 2)
+
+	// Synthesizer quota is exhausted
+	comp := newComp("ns-2", limited.Name, false)
+	assert.False(t, q.Allowed(&comp))
+
+	// Namespace quota is exhausted
+	comp = newComp("ns-1", unlimited.Name, false)
+	assert.False(t, q.Allowed(&comp))
+
+	// Replacing an in-flight synthesis doesn't consume another slot
+	assert.True(t, q.Allowed(&comps[0]))
+
+	// Plenty of room
+	comp = newComp("ns-2", unlimited.Name, false)
+	assert.True(t, q.Allowed(&comp))
+
+	// Namespace limits are optional
+	q = newQuotas(synths, comps, 0)
+	comp = newComp("ns-1", unlimited.Name, false)
+	assert.True(t, q.Allowed(&comp))
+
+	// Metrics
+	freeSynthesisSlots.Reset()
+	prev := newQuotas(synths, comps, 2)
+	prev.Report(nil)
+	assert.Equal(t, float64(0), testutil.ToFloat64(freeSynthesisSlots.WithLabelValues("synthesizer", limited.Name)))
+	assert.Equal(t, float64(0), testutil.ToFloat64(freeSynthesisSlots.WithLabelValues("namespace", "ns-1")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(freeSynthesisSlots.WithLabelValues("namespace", "ns-2")))
+	assert.Equal(t, 3, testutil.CollectAndCount(freeSynthesisSlots))
+
+	// Stale series are removed
+	newQuotas(map[string]apiv1.Synthesizer{unlimited.Name: unlimited}, comps[:2], 2).Report(prev)
+	assert.Equal(t, float64(0), testutil.ToFloat64(freeSynthesisSlots.WithLabelValues("namespace", "ns-1")))
+	assert.Equal(t, 1, testutil.CollectAndCount(freeSynthesisSlots))
+}
@@ -0,0 +1,252 @@
+package scheduling
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// rollout tracks the progress of a synthesizer's progressive (wave-based) rollout.
+//
+// It's derived entirely from the state of the synthesizer and its compositions in order
+// to preserve the scheduling controller's deterministic behavior.
+type rollout struct {
+	synth *apiv1.Synthesizer
+	comps []*apiv1.Composition // in rollout order
+
+	waves    map[types.UID]int // composition UID -> wave index
+	openWave int               // highest wave index that is currently allowed to dispatch
+	nextWave time.Time         // when the next wave will open (zero when blocked by in-progress syntheses)
+
+	failures        int
+	firstFailure    *apiv1.Composition // first failed composition in rollout order
+	halted          bool
+	nextHealthCheck time.Time // when the next composition will exceed the ready timeout
+
+	requireApproval bool
+	approvedWaves   int
+}
+
+// maxAwaitingApproval bounds the number of compositions listed in the synthesizer's status while waiting for approval.
+const maxAwaitingApproval = 100
+
+// newRollouts builds rollout state for every synthesizer that configures a rollout strategy.
+func newRollouts(synthsByName map[string]apiv1.Synthesizer, comps []apiv1.Composition, now time.Time) map[string]*rollout {
+	compsBySynth := map[string][]*apiv1.Composition{}
+	for i := range comps {
+		comp := &comps[i]
+		if comp.Spec.Synthesizer.Revision != nil {
+			continue // pinned to a previous revision
+		}
+		synth, ok := synthsByName[comp.Spec.Synthesizer.Name]
+		if !ok || synth.Spec.Rollout == nil {
+			continue
+		}
+		compsBySynth[synth.Name] = append(compsBySynth[synth.Name], comp)
+	}
+
+	rollouts := map[string]*rollout{}
+	for name, comps := range compsBySynth {
+		synth := synthsByName[name]
+		rollouts[name] = newRollout(&synth, comps, now)
+	}
+	return rollouts
+}
+
+func newRollout(synth *apiv1.Synthesizer, comps []*apiv1.Composition, now time.Time) *rollout {
+	hashes := make(map[types.UID][]byte, len(comps))
+	for _, comp := range comps {
+		hashes[comp.UID] = rolloutOrderHash(synth, comp)
+	}
+	sort.Slice(comps, func(i, j int) bool {
+		cmp := bytes.Compare(hashes[comps[i].UID], hashes[comps[j].UID])
+		if cmp != 0 {
+			return cmp > 0 // same ordering as op.Less
+		}
+		return comps[i].UID < comps[j].UID
+	})
+
+	r := &rollout{
+		synth:           synth,
+		comps:           comps,
+		halted:          synth.RolloutHalted(),
+		requireApproval: synth.Spec.Rollout.RequireApproval,
+		approvedWaves:   synth.ApprovedRolloutWaves(),
+	}
+	if threshold := synth.Spec.Rollout.FailureThreshold; threshold != nil {
+		for _, comp := range comps {
+			failed, deadline := rolloutFailed(synth, comp, now)
+			if failed {
+				if r.firstFailure == nil {
+					r.firstFailure = comp
+				}
+				r.failures++
+			}
+			r.nextHealthCheck = earliest(r.nextHealthCheck, deadline)
+		}
+		r.halted = r.halted || r.failures >= *threshold
+	}
+
+	waves := synth.Spec.Rollout.Waves
+	if len(waves) == 0 {
+		return r
+	}
+	r.waves = make(map[types.UID]int, len(comps))
+	bounds := waveBounds(waves, len(comps))
+	wave := 0
+	for i, comp := range comps {
+		for wave < len(bounds) && i >= bounds[wave] {
+			wave++
+		}
+		r.waves[comp.UID] = wave
+	}
+
+	// Find the last wave that is allowed to start
+	var lastSynthesized time.Time
+	for i, comp := range comps {
+		wave := r.waves[comp.UID]
+		if syn := comp.Status.CurrentSynthesis; syn != nil && syn.Synthesized != nil && syn.ObservedSynthesizerGeneration >= synth.Generation && syn.Synthesized.After(lastSynthesized) {
+			lastSynthesized = syn.Synthesized.Time
+		}
+		if rolloutPending(synth, comp) {
+			return r // the current wave is still in progress
+		}
+
+		// Move on to the next wave once every composition in this one has been synthesized
+		if i+1 < len(comps) && r.waves[comps[i+1].UID] == wave {
+			continue
+		}
+		if wave >= len(waves) {
+			break // the implicit final wave is complete
+		}
+		if bt := waves[wave].BakeTime; bt != nil {
+			if next := lastSynthesized.Add(bt.Duration); next.After(now) {
+				r.nextWave = next
+				return r
+			}
+		}
+		r.openWave = wave + 1
+	}
+
+	return r
+}
+
+// Halted returns true when the rollout has been halted due to too many failures.
+func (r *rollout) Halted() bool { return r != nil && r.halted }
+
+// HasWaves returns true when the rollout is gated by waves instead of the global cooldown period.
+func (r *rollout) HasWaves() bool { return r != nil && r.waves != nil }
+
+// ShouldRecordHalt returns true when the rollout has been halted but the synthesizer's status doesn't reflect it yet.
+func (r *rollout) ShouldRecordHalt(synth *apiv1.Synthesizer) bool {
+	return r.Halted() && !synth.RolloutHalted()
+}
+
+// HaltReason returns a human-readable description of why the rollout was halted.
+func (r *rollout) HaltReason(synth *apiv1.Synthesizer) string {
+	if r.firstFailure == nil {
+		return fmt.Sprintf("rollout of generation %d was halted", synth.Generation)
+	}
+	return fmt.Sprintf("%d composition(s) failed after receiving generation %d (e.g. %s/%s)", r.failures, synth.Generation, r.firstFailure.Namespace, r.firstFailure.Name)
+}
+
+// Allowed returns true when the given composition belongs to a wave that has started.
+// If not, the returned time is when the next wave is expected to start (zero if unknown).
+func (r *rollout) Allowed(comp *apiv1.Composition) (bool, time.Time) {
+	return r.waves[comp.UID] <= r.openWave, r.nextWave
+}
+
+// Approved returns true when the given composition doesn't need (further) approval to receive the synthesizer's current generation.
+// Rollouts without waves are approved as a single wave.
+func (r *rollout) Approved(comp *apiv1.Composition) bool {
+	return r == nil || !r.requireApproval || r.waves[comp.UID] < r.approvedWaves || comp.RolloutApproved(r.synth)
+}
+
+// ApprovalStatus returns the compositions that would receive the synthesizer's current generation if they were approved,
+// and whether the synthesizer's status already reflects them.
+func (r *rollout) ApprovalStatus() (refs []apiv1.CompositionRef, count int, current bool) {
+	if r.requireApproval && !r.halted {
+		for _, comp := range r.comps {
+			if comp.Synthesizing() || r.Approved(comp) {
+				continue
+			}
+			if allowed, _ := r.Allowed(comp); !allowed {
+				continue // blocked by an earlier wave
+			}
+			if reason, ok := classifyOp(r.synth, comp, comp.Status.CurrentSynthesis); !ok || reason != synthesizerModifiedOp {
+				continue
+			}
+			if count < maxAwaitingApproval {
+				refs = append(refs, apiv1.CompositionRef{Name: comp.Name, Namespace: comp.Namespace})
+			}
+			count++
+		}
+	}
+
+	status := r.synth.Status.Rollout
+	if status == nil || status.ObservedGeneration != r.synth.Generation {
+		status = &apiv1.RolloutStatus{}
+	}
+	current = status.AwaitingApprovalCount == count && slices.Equal(status.AwaitingApproval, refs)
+	return refs, count, current
+}
+
+// waveBounds returns the cumulative (exclusive) upper bound of each wave's position in the rollout order.
+func waveBounds(waves []apiv1.RolloutWave, total int) []int {
+	bounds := make([]int, len(waves))
+	var prev int
+	for i, wave := range waves {
+		n := max(wave.Count, int(math.Ceil(float64(wave.Percent)*float64(total)/100)))
+		n = min(max(n, prev), total)
+		bounds[i] = n
+		prev = n
+	}
+	return bounds
+}
+
+// rolloutPending returns true when the given composition has not yet been synthesized using the synthesizer's current generation,
+// and is expected to eventually be.
+func rolloutPending(synth *apiv1.Synthesizer, comp *apiv1.Composition) bool {
+	if comp.Synthesizing() {
+		return true
+	}
+	_, ok := classifyOp(synth, comp, comp.Status.CurrentSynthesis)
+	return ok
+}
+
+// rolloutFailed returns true when the given composition was synthesized using the synthesizer's current generation,
+// and either failed or didn't become ready within the configured timeout.
+// Otherwise, the returned time is when the composition will exceed the ready timeout (zero if not applicable).
+func rolloutFailed(synth *apiv1.Synthesizer, comp *apiv1.Composition, now time.Time) (bool, time.Time) {
+	syn := comp.Status.CurrentSynthesis
+	if syn == nil || syn.Synthesized == nil || syn.ObservedSynthesizerGeneration != synth.Generation || comp.DeletionTimestamp != nil {
+		return false, time.Time{}
+	}
+	if syn.Failed() {
+		return true, time.Time{}
+	}
+
+	timeout := synth.Spec.Rollout.ReadyTimeout
+	if timeout == nil || syn.Ready != nil {
+		return false, time.Time{}
+	}
+	deadline := syn.Synthesized.Add(timeout.Duration)
+	if now.After(deadline) {
+		return true, time.Time{}
+	}
+	return false, deadline
+}
+
+// rolloutOrderHash returns a hash that represents a composition's order in the rollout of a particular synthesizer generation.
+func rolloutOrderHash(synth *apiv1.Synthesizer, comp *apiv1.Composition) []byte {
+	hash := fnv.New64()
+	fmt.Fprintf(hash, "%s:%d:%s", synth.UID, synth.Generation, comp.UID)
+	return hash.Sum(nil)
+}
@@ -0,0 +1,315 @@
+package scheduling
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func TestWaveBounds(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Waves  []apiv1.RolloutWave
+		Total  int
+		Expect []int
+	}{
+		{
+			Name:   "canary then percentages",
+			Waves:  []apiv1.RolloutWave{{Count: 1}, {Percent: 5}, {Percent: 25}, {Percent: 100}},
+			Total:  100,
+			Expect: []int{1, 5, 25, 100},
+		},
+		{
+			Name:   "rounds up",
+			Waves:  []apiv1.RolloutWave{{Percent: 10}, {Percent: 50}},
+			Total:  3,
+			Expect: []int{1, 2},
+		},
+		{
+			Name:   "larger of count and percent",
+			Waves:  []apiv1.RolloutWave{{Count: 10, Percent: 1}, {Count: 1, Percent: 50}},
+			Total:  100,
+			Expect: []int{10, 50},
+		},
+		{
+			Name:   "monotonic",
+			Waves:  []apiv1.RolloutWave{{Count: 10}, {Count: 5}},
+			Total:  100,
+			Expect: []int{10, 10},
+		},
+		{
+			Name:   "clamped",
+			Waves:  []apiv1.RolloutWave{{Count: 10}},
+			Total:  3,
+			Expect: []int{3},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expect, waveBounds(tc.Waves, tc.Total))
+		})
+	}
+}
+
+func TestRolloutWaves(t *testing.T) {
+	now := time.Now()
+	synth := &apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	synth.UID = "test-synth-uid"
+	synth.Generation = 2
+	synth.Spec.Rollout = &apiv1.RolloutStrategy{
+		Waves: []apiv1.RolloutWave{
+			{Count: 1, BakeTime: &metav1.Duration{Duration: time.Minute}},
+			{Percent: 50},
+		},
+	}
+
+	var comps []apiv1.Composition
+	for i := 0; i < 10; i++ {
+		comp := apiv1.Composition{}
+		comp.Name = fmt.Sprintf("comp-%d", i)
+		comp.UID = types.UID(comp.Name)
+		comp.Finalizers = []string{"eno.azure.io/cleanup"}
+		comp.Spec.Synthesizer.Name = synth.Name
+		comp.Status.CurrentSynthesis = &apiv1.Synthesis{
+			ObservedSynthesizerGeneration: 1,
+			Synthesized:                   ptr.To(metav1.NewTime(now.Add(-time.Hour))),
+		}
+		comps = append(comps, comp)
+	}
+	synths := map[string]apiv1.Synthesizer{synth.Name: *synth}
+
+	countAllowed := func(r *rollout) (n int) {
+		for _, comp := range comps {
+			if ok, _ := r.Allowed(&comp); ok {
+				n++
+			}
+		}
+		return n
+	}
+	findWave := func(r *rollout, wave int) (matches []*apiv1.Composition) {
+		for i := range comps {
+			if r.waves[comps[i].UID] == wave {
+				matches = append(matches, &comps[i])
+			}
+		}
+		return matches
+	}
+
+	// Only the canary is allowed initially
+	r := newRollouts(synths, comps, now)[synth.Name]
+	require.NotNil(t, r)
+	assert.Equal(t, 1, countAllowed(r))
+	assert.Len(t, findWave(r, 0), 1)
+	assert.Len(t, findWave(r, 1), 4)
+	assert.Len(t, findWave(r, 2), 5)
+
+	// The canary has been synthesized, but is still baking
+	canary := findWave(r, 0)[0]
+	canary.Status.CurrentSynthesis.ObservedSynthesizerGeneration = synth.Generation
+	canary.Status.CurrentSynthesis.Synthesized = ptr.To(metav1.NewTime(now.Add(-time.Second)))
+
+	r = newRollouts(synths, comps, now)[synth.Name]
+	assert.Equal(t, 1, countAllowed(r))
+	assert.Equal(t, now.Add(time.Minute-time.Second), r.nextWave)
+
+	// Bake time has elapsed
+	r = newRollouts(synths, comps, now.Add(time.Minute))[synth.Name]
+	assert.Equal(t, 5, countAllowed(r))
+
+	// The second wave has no bake time, so the final implicit wave opens immediately once it's synthesized
+	for _, comp := range findWave(r, 1) {
+		comp.Status.CurrentSynthesis.ObservedSynthesizerGeneration = synth.Generation
+	}
+	r = newRollouts(synths, comps, now.Add(time.Minute))[synth.Name]
+	assert.Equal(t, 10, countAllowed(r))
+}
+
+func TestRolloutIgnoresIneligibleCompositions(t *testing.T) {
+	now := time.Now()
+	synth := apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	synth.Generation = 2
+	synth.Spec.Rollout = &apiv1.RolloutStrategy{
+		Waves: []apiv1.RolloutWave{{Count: 1, BakeTime: &metav1.Duration{Duration: time.Hour}}},
+	}
+	synths := map[string]apiv1.Synthesizer{synth.Name: synth}
+
+	var comps []apiv1.Composition
+	for i := 0; i < 2; i++ {
+		comp := apiv1.Composition{}
+		comp.Name = fmt.Sprintf("comp-%d", i)
+		comp.UID = types.UID(comp.Name)
+		comp.Finalizers = []string{"eno.azure.io/cleanup"}
+		comp.Spec.Synthesizer.Name = synth.Name
+		comp.Status.CurrentSynthesis = &apiv1.Synthesis{ObservedSynthesizerGeneration: 1, Synthesized: ptr.To(metav1.Now())}
+		comps = append(comps, comp)
+	}
+
+	r := newRollouts(synths, comps, now)[synth.Name]
+	canary, other := &comps[0], &comps[1]
+	if r.waves[canary.UID] != 0 {
+		canary, other = other, canary
+	}
+	ok, _ := r.Allowed(other)
+	assert.False(t, ok)
+
+	// The canary will never be synthesized, so it shouldn't block the rollout
+	canary.EnableIgnoreSideEffects()
+	r = newRollouts(synths, comps, now)[synth.Name]
+	ok, _ = r.Allowed(other)
+	assert.True(t, ok)
+}
+
+func TestRolloutHalting(t *testing.T) {
+	now := time.Now()
+	synth := apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	synth.Generation = 2
+	synth.Spec.Rollout = &apiv1.RolloutStrategy{
+		FailureThreshold: ptr.To(2),
+		ReadyTimeout:     &metav1.Duration{Duration: time.Minute},
+	}
+	synths := map[string]apiv1.Synthesizer{synth.Name: synth}
+
+	var comps []apiv1.Composition
+	for i := 0; i < 4; i++ {
+		comp := apiv1.Composition{}
+		comp.Name = fmt.Sprintf("comp-%d", i)
+		comp.UID = types.UID(comp.Name)
+		comp.Spec.Synthesizer.Name = synth.Name
+		comp.Status.CurrentSynthesis = &apiv1.Synthesis{ObservedSynthesizerGeneration: 1, Synthesized: ptr.To(metav1.NewTime(now))}
+		comps = append(comps, comp)
+	}
+
+	// Failures using older generations don't count
+	comps[0].Status.CurrentSynthesis.Results = []apiv1.Result{{Severity: "error"}}
+	r := newRollouts(synths, comps, now)[synth.Name]
+	assert.False(t, r.Halted())
+	assert.False(t, r.HasWaves())
+
+	// One failure is tolerated
+	comps[0].Status.CurrentSynthesis.ObservedSynthesizerGeneration = synth.Generation
+	r = newRollouts(synths, comps, now)[synth.Name]
+	assert.False(t, r.Halted())
+	assert.Equal(t, 1, r.failures)
+
+	// Compositions that are still within the ready timeout don't count, but schedule another check
+	comps[1].Status.CurrentSynthesis.ObservedSynthesizerGeneration = synth.Generation
+	r = newRollouts(synths, comps, now)[synth.Name]
+	assert.False(t, r.Halted())
+	assert.Equal(t, now.Add(time.Minute), r.nextHealthCheck)
+
+	// Ready compositions are healthy
+	comps[1].Status.CurrentSynthesis.Ready = ptr.To(metav1.NewTime(now))
+	r = newRollouts(synths, comps, now.Add(time.Hour))[synth.Name]
+	assert.False(t, r.Halted())
+	assert.True(t, r.nextHealthCheck.IsZero())
+
+	// Compositions that never became ready count as failures
+	comps[1].Status.CurrentSynthesis.Ready = nil
+	r = newRollouts(synths, comps, now.Add(time.Hour))[synth.Name]
+	assert.True(t, r.Halted())
+	assert.True(t, r.ShouldRecordHalt(&synth))
+	assert.Contains(t, r.HaltReason(&synth), "2 composition(s) failed after receiving generation 2")
+
+	// Halting is sticky for the current generation
+	synth.Status.Rollout = &apiv1.RolloutStatus{ObservedGeneration: synth.Generation, Halted: ptr.To(metav1.Now())}
+	synths[synth.Name] = synth
+	r = newRollouts(synths, comps, now)[synth.Name]
+	assert.True(t, r.Halted())
+	assert.False(t, r.ShouldRecordHalt(&synth))
+
+	// ...but not the next one
+	synth.Generation++
+	synths[synth.Name] = synth
+	r = newRollouts(synths, comps, now)[synth.Name]
+	assert.False(t, r.Halted())
+}
+
+func TestRolloutApproval(t *testing.T) {
+	now := time.Now()
+	synth := apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	synth.UID = "test-synth-uid"
+	synth.Generation = 2
+	synth.Spec.Rollout = &apiv1.RolloutStrategy{
+		RequireApproval: true,
+		Waves:           []apiv1.RolloutWave{{Count: 1}},
+	}
+	synths := map[string]apiv1.Synthesizer{synth.Name: synth}
+
+	var comps []apiv1.Composition
+	for i := 0; i < 3; i++ {
+		comp := apiv1.Composition{}
+		comp.Name = fmt.Sprintf("comp-%d", i)
+		comp.Namespace = "default"
+		comp.UID = types.UID(comp.Name)
+		comp.Finalizers = []string{"eno.azure.io/cleanup"}
+		comp.Spec.Synthesizer.Name = synth.Name
+		comp.Status.CurrentSynthesis = &apiv1.Synthesis{ObservedSynthesizerGeneration: 1, Synthesized: ptr.To(metav1.NewTime(now))}
+		comps = append(comps, comp)
+	}
+
+	// Only the first wave is awaiting approval
+	r := newRollouts(synths, comps, now)[synth.Name]
+	refs, count, current := r.ApprovalStatus()
+	assert.Equal(t, 1, count)
+	require.Len(t, refs, 1)
+	assert.False(t, current)
+	canary := r.comps[0]
+	assert.Equal(t, canary.Name, refs[0].Name)
+	assert.False(t, r.Approved(canary))
+
+	// Status is up to date
+	synth.Status.Rollout = &apiv1.RolloutStatus{ObservedGeneration: synth.Generation, AwaitingApproval: refs, AwaitingApprovalCount: count}
+	synths[synth.Name] = synth
+	r = newRollouts(synths, comps, now)[synth.Name]
+	_, _, current = r.ApprovalStatus()
+	assert.True(t, current)
+
+	// Approve the first wave
+	synth.Annotations = map[string]string{"eno.azure.io/approved-rollout": "2/1"}
+	synths[synth.Name] = synth
+	r = newRollouts(synths, comps, now)[synth.Name]
+	assert.True(t, r.Approved(canary))
+	refs, count, current = r.ApprovalStatus()
+	assert.Empty(t, refs)
+	assert.Zero(t, count)
+	assert.False(t, current)
+
+	// The next wave still requires approval
+	for i := range comps {
+		if comps[i].Name == canary.Name {
+			comps[i].Status.CurrentSynthesis.ObservedSynthesizerGeneration = synth.Generation
+		}
+	}
+	r = newRollouts(synths, comps, now)[synth.Name]
+	_, count, _ = r.ApprovalStatus()
+	assert.Equal(t, 2, count)
+
+	// Individual compositions can be approved
+	for i := range comps {
+		if comps[i].Name != canary.Name {
+			comps[i].Annotations = map[string]string{"eno.azure.io/approved-synthesizer-generation": "2"}
+			break
+		}
+	}
+	r = newRollouts(synths, comps, now)[synth.Name]
+	_, count, _ = r.ApprovalStatus()
+	assert.Equal(t, 1, count)
+
+	// Approvals don't carry over to the next generation
+	synth.Generation++
+	synths[synth.Name] = synth
+	r = newRollouts(synths, comps, now)[synth.Name]
+	_, count, _ = r.ApprovalStatus()
+	assert.Equal(t, 1, count, "only the canary wave is waiting")
+}
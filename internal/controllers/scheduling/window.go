@@ -0,0 +1,141 @@
+package scheduling
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// timeWindow is a parsed apiv1.TimeWindow.
+type timeWindow struct {
+	cron       *cronSchedule
+	duration   time.Duration
+	start, end time.Time
+}
+
+func newTimeWindow(w *apiv1.TimeWindow) (*timeWindow, error) {
+	if w.Schedule == "" {
+		if w.Start == nil || w.End == nil {
+			return nil, fmt.Errorf("either a schedule or start and end times are required")
+		}
+		return &timeWindow{start: w.Start.Time, end: w.End.Time}, nil
+	}
+
+	cron, err := parseCron(w.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("parsing schedule %q: %w", w.Schedule, err)
+	}
+	if w.Duration == nil || w.Duration.Duration <= 0 {
+		return nil, fmt.Errorf("a positive duration is required")
+	}
+	return &timeWindow{cron: cron, duration: w.Duration.Duration}, nil
+}
+
+// Open returns true when the window is open at the given time.
+// The returned time is when that might change (zero if never).
+func (w *timeWindow) Open(now time.Time) (bool, time.Time) {
+	if w.cron == nil {
+		switch {
+		case now.Before(w.start):
+			return false, w.start
+		case now.Before(w.end):
+			return true, w.end
+		default:
+			return false, time.Time{}
+		}
+	}
+
+	if prev := w.cron.Prev(now); !prev.IsZero() && now.Before(prev.Add(w.duration)) {
+		return true, prev.Add(w.duration)
+	}
+	return false, w.cron.Next(now)
+}
+
+// schedule gates deferred syntheses using a set of windows (when they're allowed) and blackouts (when they aren't).
+type schedule struct {
+	windows, blackouts []*timeWindow
+}
+
+func newSchedule(windows, blackouts []apiv1.TimeWindow) (*schedule, error) {
+	s := &schedule{}
+	for i := range windows {
+		w, err := newTimeWindow(&windows[i])
+		if err != nil {
+			return nil, fmt.Errorf("window %d: %w", i, err)
+		}
+		s.windows = append(s.windows, w)
+	}
+	for i := range blackouts {
+		w, err := newTimeWindow(&blackouts[i])
+		if err != nil {
+			return nil, fmt.Errorf("blackout %d: %w", i, err)
+		}
+		s.blackouts = append(s.blackouts, w)
+	}
+	return s, nil
+}
+
+// Allowed returns true when deferred syntheses can be dispatched at the given time.
+// Otherwise the returned time is when they might be allowed (zero if never).
+func (s *schedule) Allowed(now time.Time) (bool, time.Time) {
+	if s == nil {
+		return true, time.Time{}
+	}
+
+	open := len(s.windows) == 0
+	var next time.Time
+	for _, w := range s.windows {
+		ok, change := w.Open(now)
+		if ok {
+			open = true
+			break
+		}
+		next = earliest(next, change)
+	}
+	if !open {
+		return false, next
+	}
+
+	for _, b := range s.blackouts {
+		if ok, end := b.Open(now); ok {
+			return false, end
+		}
+	}
+	return true, time.Time{}
+}
+
+// ParseTimeWindow parses the command line representation of a time window: either a 5-field cron expression
+// followed by a duration (e.g. "0 22 * * 5 60h"), or two RFC3339 timestamps separated by a slash.
+func ParseTimeWindow(str string) (apiv1.TimeWindow, error) {
+	if start, end, ok := strings.Cut(str, "/"); ok && !strings.Contains(start, " ") {
+		s, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return apiv1.TimeWindow{}, fmt.Errorf("parsing start time: %w", err)
+		}
+		e, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			return apiv1.TimeWindow{}, fmt.Errorf("parsing end time: %w", err)
+		}
+		if !e.After(s) {
+			return apiv1.TimeWindow{}, fmt.Errorf("end time must be after the start time")
+		}
+		return apiv1.TimeWindow{Start: &metav1.Time{Time: s}, End: &metav1.Time{Time: e}}, nil
+	}
+
+	fields := strings.Fields(str)
+	if len(fields) != 6 {
+		return apiv1.TimeWindow{}, fmt.Errorf("expected a cron expression followed by a duration")
+	}
+	d, err := time.ParseDuration(fields[5])
+	if err != nil {
+		return apiv1.TimeWindow{}, fmt.Errorf("parsing duration: %w", err)
+	}
+	w := apiv1.TimeWindow{Schedule: strings.Join(fields[:5], " "), Duration: &metav1.Duration{Duration: d}}
+	if _, err := newTimeWindow(&w); err != nil {
+		return apiv1.TimeWindow{}, err
+	}
+	return w, nil
+}
@@ -0,0 +1,81 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleAllowed(t *testing.T) {
+	parse := func(strs ...string) []apiv1.TimeWindow {
+		var windows []apiv1.TimeWindow
+		for _, str := range strs {
+			w, err := ParseTimeWindow(str)
+			require.NoError(t, err, str)
+			windows = append(windows, w)
+		}
+		return windows
+	}
+	at := func(str string) time.Time {
+		ts, err := time.Parse(time.RFC3339, str)
+		require.NoError(t, err)
+		return ts
+	}
+
+	// Saturday 01:00-05:00 plus a one-off window, except for a holiday freeze
+	s, err := newSchedule(
+		parse("0 1 * * 6 4h", "2024-03-13T10:00:00Z/2024-03-13T12:00:00Z"),
+		parse("2024-03-23T00:00:00Z/2024-03-24T00:00:00Z"))
+	require.NoError(t, err)
+
+	ok, next := s.Allowed(at("2024-03-16T02:00:00Z"))
+	assert.True(t, ok, "inside recurring window")
+	assert.True(t, next.IsZero())
+
+	ok, next = s.Allowed(at("2024-03-16T05:00:00Z"))
+	assert.False(t, ok, "recurring window has closed")
+	assert.Equal(t, at("2024-03-23T01:00:00Z"), next)
+
+	ok, next = s.Allowed(at("2024-03-12T00:00:00Z"))
+	assert.False(t, ok, "one-off window opens first")
+	assert.Equal(t, at("2024-03-13T10:00:00Z"), next)
+
+	ok, _ = s.Allowed(at("2024-03-13T11:00:00Z"))
+	assert.True(t, ok, "inside one-off window")
+
+	ok, next = s.Allowed(at("2024-03-23T02:00:00Z"))
+	assert.False(t, ok, "blackout")
+	assert.Equal(t, at("2024-03-24T00:00:00Z"), next)
+
+	// No windows means always open
+	s, err = newSchedule(nil, parse("0 0 * * * 1h"))
+	require.NoError(t, err)
+	ok, _ = s.Allowed(at("2024-03-16T01:00:00Z"))
+	assert.True(t, ok)
+	ok, next = s.Allowed(at("2024-03-16T00:30:00Z"))
+	assert.False(t, ok)
+	assert.Equal(t, at("2024-03-16T01:00:00Z"), next)
+
+	// Nil schedules don't block anything
+	ok, _ = (*schedule)(nil).Allowed(time.Now())
+	assert.True(t, ok)
+}
+
+func TestParseTimeWindow(t *testing.T) {
+	w, err := ParseTimeWindow("0 22 * * 5 60h")
+	require.NoError(t, err)
+	assert.Equal(t, "0 22 * * 5", w.Schedule)
+	assert.Equal(t, 60*time.Hour, w.Duration.Duration)
+
+	w, err = ParseTimeWindow("2024-03-13T10:00:00Z/2024-03-13T12:00:00Z")
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, w.End.Sub(w.Start.Time))
+
+	for _, str := range []string{"", "0 22 * * 5", "0 22 * * 5 -1h", "0 25 * * 5 1h", "2024-03-13T12:00:00Z/2024-03-13T10:00:00Z", "today/tomorrow"} {
+		_, err := ParseTimeWindow(str)
+		assert.Error(t, err, str)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -19,6 +20,7 @@ import (
 
 	apiv1 "github.com/Azure/eno/api/v1"
 	"github.com/Azure/eno/internal/manager"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
 )
 
 type Config struct {
@@ -33,6 +35,9 @@ type Config struct {
 	NodeAffinityValue string
 
 	ContainerCreationTimeout time.Duration
+
+	// Max number of service-backed syntheses that will be run at the same time.
+	ServiceConcurrency int
 }
 
 type podLifecycleController struct {
@@ -92,9 +97,7 @@ func (c *podLifecycleController) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	// Tolerate missing synths since we may still need to cleanup
-	syn := &apiv1.Synthesizer{}
-	syn.Name = comp.Spec.Synthesizer.Name
-	err = c.client.Get(ctx, client.ObjectKeyFromObject(syn), syn)
+	syn, err := getSynthesizer(ctx, c.client, comp)
 	// It's only safe to ignore as a missing synth if we have already started synthesis,
 	// otherwise creating the synth and composition around the same time could result in a deadlock
 	// if the composition is processed before the synth hits the informer cache.
@@ -140,6 +143,29 @@ func (c *podLifecycleController) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil
 	}
 
+	// Synthesizers backed by a service don't need pods
+	if syn.Spec.Service != nil {
+		return ctrl.Result{}, nil
+	}
+
+	// The kubelet resolves synthesisEnv references in the pod's namespace, so they can't reach into other namespaces
+	if hasEnvRefs(comp) && comp.Namespace != c.config.PodNamespace {
+		if err := failSynthesis(ctx, c.client, comp, syn, fmt.Sprintf("synthesisEnv valueFrom references are only supported for compositions in the synthesizer pod namespace %q", c.config.PodNamespace)); err != nil {
+			return ctrl.Result{}, err
+		}
+		logger.V(0).Info("synthesis failed because synthesisEnv references can't be resolved outside of the synthesizer pod namespace")
+		return ctrl.Result{}, nil
+	}
+
+	// Stop retrying once the synthesizer's attempt budget has been exhausted
+	if max := syn.Spec.MaxAttempts; max != nil && comp.Status.CurrentSynthesis.Attempts >= *max {
+		if err := failSynthesis(ctx, c.client, comp, syn, fmt.Sprintf("synthesis failed after %d attempts", comp.Status.CurrentSynthesis.Attempts)); err != nil {
+			return ctrl.Result{}, err
+		}
+		logger.V(0).Info("synthesis failed after exhausting the synthesizer's max attempts", "attempts", comp.Status.CurrentSynthesis.Attempts)
+		return ctrl.Result{}, nil
+	}
+
 	// Back off to avoid constantly re-synthesizing impossible compositions (unlikely but possible)
 	if shouldBackOffPodCreation(comp) {
 		const base = time.Millisecond * 250
@@ -168,7 +194,10 @@ func (c *podLifecycleController) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	// If we made it this far it's safe to create a pod
-	pod := newPod(c.config, comp, syn)
+	pod, err := newPod(c.config, comp, syn)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("building pod: %w", err)
+	}
 	err = c.client.Create(ctx, pod)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("creating pod: %w", err)
@@ -195,6 +224,52 @@ func (c *podLifecycleController) Reconcile(ctx context.Context, req ctrl.Request
 	return ctrl.Result{}, nil
 }
 
+// failSynthesis completes the current synthesis as failed with the given message without running the synthesizer.
+func failSynthesis(ctx context.Context, cli client.Client, comp *apiv1.Composition, syn *apiv1.Synthesizer, msg string) error {
+	current := comp.Status.CurrentSynthesis
+	results := append(slices.Clone(current.Results), apiv1.Result{
+		Message:  msg,
+		Severity: krmv1.ResultSeverityError,
+	})
+	patch := []map[string]any{
+		{"op": "test", "path": "/status/currentSynthesis/uuid", "value": current.UUID},
+		{"op": "test", "path": "/status/currentSynthesis/synthesized", "value": nil},
+		{"op": "add", "path": "/status/currentSynthesis/synthesized", "value": metav1.Now()},
+		{"op": "add", "path": "/status/currentSynthesis/observedSynthesizerGeneration", "value": syn.Generation},
+		{"op": "add", "path": "/status/currentSynthesis/inputRevisions", "value": comp.Status.InputRevisions},
+		{"op": "add", "path": "/status/currentSynthesis/results", "value": results},
+	}
+	patchJS, err := json.Marshal(&patch)
+	if err != nil {
+		return fmt.Errorf("encoding patch: %w", err)
+	}
+
+	if err := cli.Status().Patch(ctx, comp, client.RawPatch(types.JSONPatchType, patchJS)); err != nil {
+		return fmt.Errorf("marking synthesis as failed: %w", err)
+	}
+	return nil
+}
+
+// getSynthesizer returns the synthesizer that should be used to synthesize the given composition.
+// Compositions pinned to a particular revision get the synthesizer as it existed at that generation.
+func getSynthesizer(ctx context.Context, cli client.Reader, comp *apiv1.Composition) (*apiv1.Synthesizer, error) {
+	ref := comp.Spec.Synthesizer
+	if ref.Revision == nil {
+		syn := &apiv1.Synthesizer{}
+		syn.Name = ref.Name
+		err := cli.Get(ctx, client.ObjectKeyFromObject(syn), syn)
+		return syn, err
+	}
+
+	rev := &apiv1.SynthesizerRevision{}
+	rev.Name = apiv1.SynthesizerRevisionName(ref.Name, *ref.Revision)
+	err := cli.Get(ctx, client.ObjectKeyFromObject(rev), rev)
+	if err != nil {
+		return &apiv1.Synthesizer{}, err
+	}
+	return rev.Synthesizer(), nil
+}
+
 func (c *podLifecycleController) reconcileDeletedComposition(ctx context.Context, comp *apiv1.Composition) (ctrl.Result, error) {
 	logger := logr.FromContextOrDiscard(ctx)
 
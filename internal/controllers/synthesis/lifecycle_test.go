@@ -46,7 +46,7 @@ func TestCompositionDeletion(t *testing.T) {
 
 	require.NoError(t, NewPodLifecycleController(mgr.Manager, minimalTestConfig))
 	require.NoError(t, NewSliceCleanupController(mgr.Manager))
-	require.NoError(t, scheduling.NewController(mgr.Manager, 10, 2*time.Second))
+	require.NoError(t, scheduling.NewController(mgr.Manager, &scheduling.Config{ConcurrencyLimit: 10, CooldownPeriod: 2 * time.Second}))
 	mgr.Start(t)
 
 	syn := &apiv1.Synthesizer{}
@@ -125,7 +125,7 @@ func TestDeleteCompositionWhenSynthesizerMissing(t *testing.T) {
 
 	require.NoError(t, NewPodLifecycleController(mgr.Manager, minimalTestConfig))
 	require.NoError(t, NewSliceCleanupController(mgr.Manager))
-	require.NoError(t, scheduling.NewController(mgr.Manager, 10, 2*time.Second))
+	require.NoError(t, scheduling.NewController(mgr.Manager, &scheduling.Config{ConcurrencyLimit: 10, CooldownPeriod: 2 * time.Second}))
 	mgr.Start(t)
 
 	syn := &apiv1.Synthesizer{}
@@ -669,3 +669,43 @@ func TestShouldDeletePod(t *testing.T) {
 		})
 	}
 }
+
+func TestFailSynthesis(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	cli := testutil.NewClient(t)
+
+	synth := &apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	synth.Generation = 3
+	synth.Spec.MaxAttempts = ptr.To(2)
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Spec.Synthesizer.Name = synth.Name
+	require.NoError(t, cli.Create(ctx, comp))
+
+	comp.Status.InputRevisions = []apiv1.InputRevisions{{Key: "foo", ResourceVersion: "1"}}
+	comp.Status.CurrentSynthesis = &apiv1.Synthesis{
+		UUID:     "test-uuid",
+		Attempts: 2,
+		Results:  []apiv1.Result{{Message: "exit status 1", Severity: "error"}},
+	}
+	require.NoError(t, cli.Status().Update(ctx, comp))
+
+	require.NoError(t, failSynthesis(ctx, cli, comp, synth, "synthesis failed after 2 attempts"))
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	syn := comp.Status.CurrentSynthesis
+	assert.NotNil(t, syn.Synthesized)
+	assert.True(t, syn.Failed())
+	assert.Equal(t, int64(3), syn.ObservedSynthesizerGeneration)
+	assert.Equal(t, comp.Status.InputRevisions, syn.InputRevisions)
+	assert.Equal(t, []apiv1.Result{
+		{Message: "exit status 1", Severity: "error"},
+		{Message: "synthesis failed after 2 attempts", Severity: "error"},
+	}, syn.Results)
+
+	// The synthesis can't be failed twice
+	require.Error(t, failSynthesis(ctx, cli, comp, synth, "synthesis failed after 2 attempts"))
+}
@@ -1,8 +1,11 @@
 package synthesis
 
 import (
+	"fmt"
+	"path"
 	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/imdario/mergo"
 	corev1 "k8s.io/api/core/v1"
@@ -13,7 +16,7 @@ import (
 	"github.com/Azure/eno/internal/manager"
 )
 
-func newPod(cfg *Config, comp *apiv1.Composition, syn *apiv1.Synthesizer) *corev1.Pod {
+func newPod(cfg *Config, comp *apiv1.Composition, syn *apiv1.Synthesizer) (*corev1.Pod, error) {
 	pod := &corev1.Pod{}
 	pod.GenerateName = "synthesis-"
 	pod.Namespace = cfg.PodNamespace
@@ -49,9 +52,21 @@ func newPod(cfg *Config, comp *apiv1.Composition, syn *apiv1.Synthesizer) *corev
 			Name:  "SYNTHESIS_ATTEMPT",
 			Value: strconv.Itoa(comp.Status.CurrentSynthesis.Attempts + 1), // we write the next attempt _after_ pod creation
 		},
+		{
+			Name:  "PIPELINE_STEP",
+			Value: strconv.Itoa(len(syn.Spec.Functions)), // the executor container runs the last step
+		},
 	}
 
 	for _, ev := range filterEnv(env, comp.Spec.SynthesisEnv) {
+		if src := ev.ValueFrom; src != nil {
+			// resolved by the kubelet, so the composition must be in the pod's namespace (see hasEnvRefs)
+			env = append(env, corev1.EnvVar{Name: ev.Name, ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef:    src.SecretKeyRef.DeepCopy(),
+				ConfigMapKeyRef: src.ConfigMapKeyRef.DeepCopy(),
+			}})
+			continue
+		}
 		env = append(env, corev1.EnvVar{Name: ev.Name, Value: ev.Value})
 	}
 
@@ -182,13 +197,74 @@ func newPod(cfg *Config, comp *apiv1.Composition, syn *apiv1.Synthesizer) *corev
 			pod.Spec.Affinity.NodeAffinity = syn.Spec.PodOverrides.Affinity.NodeAffinity
 		}
 	}
-	return pod
+
+	// tolerations are additive so the synthesizer can't remove the one required by the controller's config
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, syn.Spec.PodOverrides.Tolerations...)
+	pod.Spec.NodeSelector = syn.Spec.PodOverrides.NodeSelector
+	pod.Spec.PriorityClassName = syn.Spec.PodOverrides.PriorityClassName
+	pod.Spec.RuntimeClassName = syn.Spec.PodOverrides.RuntimeClassName
+	pod.Spec.ImagePullSecrets = syn.Spec.PodOverrides.ImagePullSecrets
+
+	executor := &pod.Spec.Containers[0]
+	executor.ImagePullPolicy = syn.Spec.PodOverrides.ImagePullPolicy
+	if syn.Spec.PodOverrides.SecurityContext != nil {
+		err := mergo.Merge(executor.SecurityContext, syn.Spec.PodOverrides.SecurityContext.DeepCopy(), mergo.WithOverride, mergo.WithoutDereference)
+		if err != nil {
+			return nil, fmt.Errorf("merging security context overrides: %w", err)
+		}
+	}
+
+	// the shared volume is used to install the executor binary and pass state between pipeline steps, so it can't be replaced or shadowed
+	for _, vol := range syn.Spec.PodOverrides.Volumes {
+		if vol.Name != "sharedfs" {
+			pod.Spec.Volumes = append(pod.Spec.Volumes, vol)
+		}
+	}
+	for _, mount := range syn.Spec.PodOverrides.VolumeMounts {
+		if mount.Name != "sharedfs" && !isReservedMountPath(mount.MountPath) {
+			executor.VolumeMounts = append(executor.VolumeMounts, mount)
+		}
+	}
+
+	// each step of a pipeline runs in its own container, sequentially
+	// earlier steps are run by init containers that pass their output to the next step through the shared volume
+	for i := range syn.Spec.Functions {
+		step := executor.DeepCopy()
+		step.Name = "synthesizer"
+		if i > 0 {
+			fn := syn.Spec.Functions[i-1]
+			step.Name = "fn-" + fn.Name
+			step.Image = fn.Image
+		}
+		for j := range step.Env {
+			if step.Env[j].Name == "PIPELINE_STEP" {
+				step.Env[j].Value = strconv.Itoa(i)
+			}
+		}
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, *step)
+	}
+	if n := len(syn.Spec.Functions); n > 0 {
+		executor.Image = syn.Spec.Functions[n-1].Image
+	}
+
+	return pod, nil
+}
+
+// isReservedMountPath returns true when a volume mounted at the given path would shadow (part of) the shared volume.
+func isReservedMountPath(p string) bool {
+	p = path.Clean(p)
+	return p == "/" || p == "/eno" || strings.HasPrefix(p, "/eno/")
 }
 
 func podIsCurrent(comp *apiv1.Composition, pod *corev1.Pod) bool {
 	return pod.Labels != nil && comp.Status.CurrentSynthesis != nil && comp.Status.CurrentSynthesis.UUID == pod.Labels["eno.azure.io/synthesis-uuid"]
 }
 
+// hasEnvRefs returns true when the composition's synthesisEnv references Secrets or ConfigMaps.
+func hasEnvRefs(comp *apiv1.Composition) bool {
+	return slices.ContainsFunc(comp.Spec.SynthesisEnv, func(ev apiv1.EnvVar) bool { return ev.ValueFrom != nil })
+}
+
 // filterEnv returns env taking out any items that have the same name as
 // any item in filter.
 func filterEnv(filter []corev1.EnvVar, env []apiv1.EnvVar) []apiv1.EnvVar {
@@ -1,6 +1,7 @@
 package synthesis
 
 import (
+	"strconv"
 	"testing"
 
 	apiv1 "github.com/Azure/eno/api/v1"
@@ -10,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 )
 
 var newPodTests = []struct {
@@ -124,6 +126,31 @@ var newPodTests = []struct {
 			assert.Contains(t, p.Spec.Containers[0].Env, corev1.EnvVar{Name: "COMPOSITION_NAME", Value: "test-composition"})
 		},
 	},
+	{
+		Name: "synthesis env from secret is referenced by the pod",
+		Comp: func() *apiv1.Composition {
+			comp := &apiv1.Composition{}
+			comp.Name = "test-composition"
+			comp.Namespace = "test-composition-ns"
+			comp.Generation = 123
+			comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "test-uuid"}
+			comp.Spec.SynthesisEnv = []apiv1.EnvVar{
+				{Name: "some_env", Value: "some-val"},
+				{Name: "secret_env", ValueFrom: &apiv1.EnvVarSource{Resynthesize: true, SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "some-secret"},
+					Key:                  "foo",
+				}}},
+			}
+			return comp
+		}(),
+		Assert: func(t *testing.T, p *corev1.Pod) {
+			assert.Contains(t, p.Spec.Containers[0].Env, corev1.EnvVar{Name: "some_env", Value: "some-val"})
+			assert.Contains(t, p.Spec.Containers[0].Env, corev1.EnvVar{Name: "secret_env", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "some-secret"},
+				Key:                  "foo",
+			}}})
+		},
+	},
 	{
 		Name: "With affinity overrides",
 		Assert: func(t *testing.T, p *corev1.Pod) {
@@ -211,6 +238,105 @@ var newPodTests = []struct {
 			},
 		},
 	},
+	{
+		Name: "with pod spec overrides",
+		Cfg: &Config{
+			TaintTolerationKey: "foo",
+			NodeAffinityKey:    "foo",
+		},
+		Synth: &apiv1.Synthesizer{
+			Spec: apiv1.SynthesizerSpec{
+				PodOverrides: apiv1.PodOverrides{
+					Tolerations:       []corev1.Toleration{{Key: "bar", Operator: corev1.TolerationOpExists}},
+					NodeSelector:      map[string]string{"kubernetes.io/os": "linux"},
+					PriorityClassName: "test-priority",
+					RuntimeClassName:  ptr.To("gvisor"),
+					ImagePullSecrets:  []corev1.LocalObjectReference{{Name: "test-secret"}},
+					ImagePullPolicy:   corev1.PullAlways,
+					Volumes: []corev1.Volume{
+						{Name: "sharedfs"},
+						{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "cache", MountPath: "/eno/"},
+						{Name: "cache", MountPath: "/eno/executor"},
+						{Name: "cache", MountPath: "/"},
+						{Name: "cache", MountPath: "/enofoo"},
+						{Name: "cache", MountPath: "/cache"},
+					},
+					SecurityContext: &corev1.SecurityContext{
+						RunAsUser:              ptr.To(int64(1000)),
+						ReadOnlyRootFilesystem: ptr.To(false),
+					},
+				},
+			},
+		},
+		Assert: func(t *testing.T, p *corev1.Pod) {
+			require.Len(t, p.Spec.Tolerations, 2)
+			assert.Equal(t, "foo", p.Spec.Tolerations[0].Key)
+			assert.Equal(t, "bar", p.Spec.Tolerations[1].Key)
+
+			assert.Equal(t, map[string]string{"kubernetes.io/os": "linux"}, p.Spec.NodeSelector)
+			assert.Equal(t, "test-priority", p.Spec.PriorityClassName)
+			assert.Equal(t, "gvisor", *p.Spec.RuntimeClassName)
+			assert.Equal(t, []corev1.LocalObjectReference{{Name: "test-secret"}}, p.Spec.ImagePullSecrets)
+
+			executor := p.Spec.Containers[0]
+			assert.Equal(t, corev1.PullAlways, executor.ImagePullPolicy)
+			assert.Equal(t, corev1.PullPolicy(""), p.Spec.InitContainers[0].ImagePullPolicy)
+
+			require.Len(t, p.Spec.Volumes, 2)
+			assert.NotNil(t, p.Spec.Volumes[0].EmptyDir)
+			assert.Equal(t, corev1.StorageMediumMemory, p.Spec.Volumes[0].EmptyDir.Medium)
+			assert.Equal(t, "cache", p.Spec.Volumes[1].Name)
+
+			require.Len(t, executor.VolumeMounts, 3)
+			assert.Equal(t, "/eno", executor.VolumeMounts[0].MountPath)
+			assert.Equal(t, "/enofoo", executor.VolumeMounts[1].MountPath)
+			assert.Equal(t, "/cache", executor.VolumeMounts[2].MountPath)
+
+			sc := executor.SecurityContext
+			assert.Equal(t, int64(1000), *sc.RunAsUser)
+			assert.False(t, *sc.ReadOnlyRootFilesystem)
+			assert.Equal(t, int64(65532), *sc.RunAsGroup)
+			assert.False(t, *sc.AllowPrivilegeEscalation)
+			assert.Equal(t, []corev1.Capability{"ALL"}, sc.Capabilities.Drop)
+		},
+	},
+	{
+		Name: "with functions",
+		Synth: &apiv1.Synthesizer{
+			Spec: apiv1.SynthesizerSpec{
+				Image: "synth-image",
+				Functions: []apiv1.SynthesizerFunction{
+					{Name: "inject-labels", Image: "labels-image"},
+					{Name: "mutate", Image: "mutate-image"},
+				},
+				PodOverrides: apiv1.PodOverrides{
+					ImagePullPolicy: corev1.PullAlways,
+				},
+			},
+		},
+		Assert: func(t *testing.T, p *corev1.Pod) {
+			require.Len(t, p.Spec.InitContainers, 3)
+			assert.Equal(t, "synth-installer", p.Spec.InitContainers[0].Name)
+
+			steps := append(p.Spec.InitContainers[1:], p.Spec.Containers...)
+			for i, expected := range []struct{ Name, Image string }{
+				{"synthesizer", "synth-image"},
+				{"fn-inject-labels", "labels-image"},
+				{"executor", "mutate-image"},
+			} {
+				step := steps[i]
+				assert.Equal(t, expected.Name, step.Name)
+				assert.Equal(t, expected.Image, step.Image)
+				assert.Equal(t, []string{"/eno/executor"}, step.Command)
+				assert.Equal(t, corev1.PullAlways, step.ImagePullPolicy)
+				assert.Contains(t, step.Env, corev1.EnvVar{Name: "PIPELINE_STEP", Value: strconv.Itoa(i)})
+				assert.Contains(t, step.Env, corev1.EnvVar{Name: "COMPOSITION_NAME", Value: "test-composition"})
+			}
+		},
+	},
 }
 
 func TestNewPod(t *testing.T) {
@@ -236,7 +362,8 @@ func TestNewPod(t *testing.T) {
 		syn.Generation = 234
 
 		t.Run(tc.Name, func(t *testing.T) {
-			pod := newPod(tc.Cfg, tc.Comp, syn)
+			pod, err := newPod(tc.Cfg, tc.Comp, syn)
+			require.NoError(t, err)
 			tc.Assert(t, pod)
 		})
 	}
@@ -0,0 +1,125 @@
+package synthesis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/Azure/eno/internal/discovery"
+	"github.com/Azure/eno/internal/execution"
+	"github.com/Azure/eno/internal/manager"
+)
+
+// serviceSynthesisController synthesizes compositions whose synthesizer is backed by a long-running service.
+// The executor runs in this process instead of a new pod, so warm synthesizers aren't bound by pod startup latency.
+type serviceSynthesisController struct {
+	client        client.Client
+	noCacheReader client.Reader
+	executor      *execution.Executor
+}
+
+func NewServiceSynthesisController(mgr ctrl.Manager, cfg *Config) error {
+	disc, err := discovery.NewCache(mgr.GetConfig(), 2)
+	if err != nil {
+		return err
+	}
+
+	c := &serviceSynthesisController{
+		client:        mgr.GetClient(),
+		noCacheReader: mgr.GetAPIReader(),
+		executor: &execution.Executor{
+			Reader:  mgr.GetAPIReader(),
+			Writer:  mgr.GetClient(),
+			Handler: execution.NewServiceHandler(&http.Client{}),
+			Schemas: disc,
+		},
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1.Composition{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: max(cfg.ServiceConcurrency, 1)}).
+		WithLogConstructor(manager.NewLogConstructor(mgr, "serviceSynthesisController")).
+		Complete(c)
+}
+
+func (c *serviceSynthesisController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	comp := &apiv1.Composition{}
+	err := c.client.Get(ctx, req.NamespacedName, comp)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(fmt.Errorf("getting composition resource: %w", err))
+	}
+
+	syn := comp.Status.CurrentSynthesis
+	if comp.DeletionTimestamp != nil || syn == nil || syn.UUID == "" || syn.Synthesized != nil {
+		return ctrl.Result{}, nil
+	}
+
+	synth, err := getSynthesizer(ctx, c.client, comp)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(fmt.Errorf("getting synthesizer: %w", err))
+	}
+	if synth.Spec.Service == nil {
+		return ctrl.Result{}, nil // synthesized by a pod
+	}
+	logger = logger.WithValues("compositionName", comp.Name,
+		"compositionNamespace", comp.Namespace,
+		"compositionGeneration", comp.Generation,
+		"synthesisID", syn.UUID,
+		"synthesizerName", synth.Name,
+		"synthesizerGeneration", synth.Generation)
+
+	// The informer may not have caught up with the previous attempt yet
+	if err := c.noCacheReader.Get(ctx, req.NamespacedName, comp); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(fmt.Errorf("getting composition without cache: %w", err))
+	}
+	if comp.Status.CurrentSynthesis == nil || comp.Status.CurrentSynthesis.UUID != syn.UUID || comp.Status.CurrentSynthesis.Synthesized != nil {
+		return ctrl.Result{}, nil
+	}
+	syn = comp.Status.CurrentSynthesis
+
+	// synthesisEnv references are resolved by the kubelet, which isn't involved here
+	if hasEnvRefs(comp) {
+		if err := failSynthesis(ctx, c.client, comp, synth, "synthesisEnv valueFrom references aren't supported by service-backed synthesizers"); err != nil {
+			return ctrl.Result{}, err
+		}
+		logger.V(0).Info("synthesis failed because service-backed synthesizers don't support synthesisEnv references")
+		return ctrl.Result{}, nil
+	}
+
+	// Record the attempt first, just like pod creation. The executor skips stale attempts.
+	attempt := syn.Attempts + 1
+	patch := []map[string]any{
+		{"op": "test", "path": "/status/currentSynthesis/uuid", "value": syn.UUID},
+		{"op": "test", "path": "/status/currentSynthesis/synthesized", "value": nil},
+		{"op": "replace", "path": "/status/currentSynthesis/attempts", "value": attempt},
+	}
+	patchJS, err := json.Marshal(&patch)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("encoding patch: %w", err)
+	}
+	if err := c.client.Status().Patch(ctx, comp, client.RawPatch(types.JSONPatchType, patchJS)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("updating synthesis attempts: %w", err)
+	}
+	sytheses.Inc()
+
+	env := &execution.Env{
+		CompositionName:      comp.Name,
+		CompositionNamespace: comp.Namespace,
+		SynthesisUUID:        syn.UUID,
+		SynthesisAttempt:     attempt,
+	}
+	if err := c.executor.Synthesize(logr.NewContext(ctx, logger), env); err != nil {
+		return ctrl.Result{}, fmt.Errorf("synthesizing (attempt %d): %w", attempt, err)
+	}
+	logger.V(0).Info("synthesized using service")
+
+	return ctrl.Result{}, nil
+}
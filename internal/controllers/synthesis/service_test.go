@@ -0,0 +1,84 @@
+package synthesis
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/Azure/eno/internal/execution"
+	"github.com/Azure/eno/internal/testutil"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestServiceSynthesis(t *testing.T) {
+	ctx := testutil.NewContext(t)
+	cli := testutil.NewClient(t)
+
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(&krmv1.ResourceList{Items: []*unstructured.Unstructured{{
+			Object: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]any{"name": "test", "namespace": "default"},
+			},
+		}}})
+	}))
+	defer srv.Close()
+
+	c := &serviceSynthesisController{
+		client:        cli,
+		noCacheReader: cli,
+		executor:      &execution.Executor{Reader: cli, Writer: cli, Handler: execution.NewServiceHandler(srv.Client())},
+	}
+
+	synth := &apiv1.Synthesizer{}
+	synth.Name = "test-synth"
+	synth.Spec.Service = &apiv1.SynthesizerService{URL: srv.URL}
+	require.NoError(t, cli.Create(ctx, synth))
+
+	podSynth := &apiv1.Synthesizer{}
+	podSynth.Name = "pod-synth"
+	require.NoError(t, cli.Create(ctx, podSynth))
+
+	newComp := func(name, synth string) *apiv1.Composition {
+		comp := &apiv1.Composition{}
+		comp.Name = name
+		comp.Namespace = "default"
+		comp.Spec.Synthesizer.Name = synth
+		require.NoError(t, cli.Create(ctx, comp))
+
+		comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "test-uuid"}
+		require.NoError(t, cli.Status().Update(ctx, comp))
+		return comp
+	}
+	comp := newComp("test-comp", synth.Name)
+	podComp := newComp("pod-comp", podSynth.Name)
+
+	for _, comp := range []*apiv1.Composition{comp, podComp} {
+		_, err := c.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(comp)})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, calls)
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	require.NotNil(t, comp.Status.CurrentSynthesis.Synthesized)
+	assert.Equal(t, 1, comp.Status.CurrentSynthesis.Attempts)
+	assert.Len(t, comp.Status.CurrentSynthesis.ResourceSlices, 1)
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(podComp), podComp))
+	assert.Nil(t, podComp.Status.CurrentSynthesis.Synthesized, "synthesized by a pod")
+
+	// Completed syntheses aren't repeated
+	_, err := c.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(comp)})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
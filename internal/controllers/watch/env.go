@@ -0,0 +1,148 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/Azure/eno/internal/manager"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// envController resynthesizes compositions when Secrets or ConfigMaps referenced by their synthesisEnv
+// (with resynthesize == true) have changed since the current synthesis.
+type envController struct {
+	client    client.Client
+	refs      client.Reader // metadata of Secrets and ConfigMaps in the synthesizer pod namespace
+	namespace string
+}
+
+// NewEnvController watches the metadata of Secrets and ConfigMaps in the given namespace, which is the only
+// namespace synthesisEnv references can be resolved in (the synthesizer pod namespace).
+// It's opt-in since it requires list/watch access to Secrets in that namespace.
+func NewEnvController(mgr ctrl.Manager, namespace string) error {
+	// The manager's cache watches ConfigMaps cluster-wide when they're synthesizer inputs,
+	// so the references are watched by a separate cache that's limited to the namespace.
+	refs, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme:            mgr.GetScheme(),
+		Mapper:            mgr.GetRESTMapper(),
+		DefaultNamespaces: map[string]cache.Config{namespace: {}},
+	})
+	if err != nil {
+		return fmt.Errorf("constructing env reference cache: %w", err)
+	}
+	if err := mgr.Add(refs); err != nil {
+		return err
+	}
+	c := &envController{client: mgr.GetClient(), refs: refs, namespace: namespace}
+
+	secret := &metav1.PartialObjectMetadata{}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+
+	cm := &metav1.PartialObjectMetadata{}
+	cm.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("envWatchController").
+		For(&apiv1.Composition{}).
+		WatchesRawSource(source.Kind(refs, secret, handler.TypedEnqueueRequestsFromMapFunc(c.mapEnvRef("Secret")))).
+		WatchesRawSource(source.Kind(refs, cm, handler.TypedEnqueueRequestsFromMapFunc(c.mapEnvRef("ConfigMap")))).
+		WithLogConstructor(manager.NewLogConstructor(mgr, "envWatchController")).
+		Complete(c)
+}
+
+// mapEnvRef enqueues the compositions that reference the given object, so changes to other objects are dropped.
+func (c *envController) mapEnvRef(kind string) handler.TypedMapFunc[*metav1.PartialObjectMetadata, reconcile.Request] {
+	return func(ctx context.Context, obj *metav1.PartialObjectMetadata) []reconcile.Request {
+		list := &apiv1.CompositionList{}
+		err := c.client.List(ctx, list, client.InNamespace(obj.GetNamespace()), client.MatchingFields{
+			manager.IdxCompositionsByEnvRef: path.Join(kind, obj.GetName()),
+		})
+		if err != nil {
+			logr.FromContextOrDiscard(ctx).Error(err, "listing compositions by env reference")
+			return nil
+		}
+
+		reqs := make([]reconcile.Request, len(list.Items))
+		for i, comp := range list.Items {
+			reqs[i] = reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&comp)}
+		}
+		return reqs
+	}
+}
+
+func (c *envController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+
+	comp := &apiv1.Composition{}
+	err := c.client.Get(ctx, req.NamespacedName, comp)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// Compositions that are being synthesized will pick up the latest values anyway.
+	// References can't be resolved outside of the synthesizer pod namespace.
+	synthesis := comp.Status.CurrentSynthesis
+	if comp.Namespace != c.namespace || synthesis == nil || synthesis.Synthesized == nil || comp.DeletionTimestamp != nil || comp.ShouldForceResynthesis() || comp.ShouldIgnoreSideEffects() {
+		return ctrl.Result{}, nil
+	}
+
+	for _, ev := range comp.Spec.SynthesisEnv {
+		src := ev.ValueFrom
+		if src == nil || !src.Resynthesize {
+			continue
+		}
+
+		meta := &metav1.PartialObjectMetadata{}
+		switch {
+		case src.SecretKeyRef != nil:
+			meta.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+			meta.Name = src.SecretKeyRef.Name
+		case src.ConfigMapKeyRef != nil:
+			meta.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+			meta.Name = src.ConfigMapKeyRef.Name
+		default:
+			continue
+		}
+
+		err = c.refs.Get(ctx, types.NamespacedName{Name: meta.Name, Namespace: comp.Namespace}, meta)
+		if err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("getting %s %q: %w", meta.Kind, meta.Name, err)
+		}
+		// Syntheses that failed before resolving the reference don't record its revision
+		rev, ok := envRevision(synthesis, ev.Name)
+		if !ok || rev == meta.ResourceVersion {
+			continue
+		}
+
+		comp.ForceResynthesis()
+		err = c.client.Update(ctx, comp)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("updating composition: %w", err)
+		}
+		logger.V(0).Info("resynthesizing because a synthesis env reference changed", "compositionName", comp.Name, "compositionNamespace", comp.Namespace, "envVar", ev.Name)
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func envRevision(synthesis *apiv1.Synthesis, name string) (string, bool) {
+	for _, rev := range synthesis.EnvRevisions {
+		if rev.Key == name {
+			return rev.ResourceVersion, true
+		}
+	}
+	return "", false
+}
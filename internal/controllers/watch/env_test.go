@@ -0,0 +1,79 @@
+package watch
+
+import (
+	"context"
+	"testing"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/Azure/eno/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestEnvControllerResynthesis(t *testing.T) {
+	ctx := context.Background()
+	cli := testutil.NewClient(t)
+	c := &envController{client: cli, refs: cli, namespace: "default"}
+
+	secret := &corev1.Secret{}
+	secret.Name = "test-secret"
+	secret.Namespace = "default"
+	secret.Data = map[string][]byte{"foo": []byte("bar")}
+	require.NoError(t, cli.Create(ctx, secret))
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Spec.SynthesisEnv = []apiv1.EnvVar{
+		{Name: "IGNORED", ValueFrom: &apiv1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+			Key:                  "foo",
+		}}},
+		{Name: "FOO", ValueFrom: &apiv1.EnvVarSource{
+			Resynthesize: true,
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+				Key:                  "foo",
+			},
+		}},
+	}
+	require.NoError(t, cli.Create(ctx, comp))
+
+	now := metav1.Now()
+	comp.Status.CurrentSynthesis = &apiv1.Synthesis{
+		UUID:         "test-uuid",
+		Synthesized:  &now,
+		EnvRevisions: []apiv1.InputRevisions{{Key: "FOO", ResourceVersion: secret.ResourceVersion}},
+	}
+	require.NoError(t, cli.Status().Update(ctx, comp))
+
+	reconcile := func() *apiv1.Composition {
+		_, err := c.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(comp)})
+		require.NoError(t, err)
+
+		current := &apiv1.Composition{}
+		require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), current))
+		return current
+	}
+
+	// Unchanged
+	assert.False(t, reconcile().ShouldForceResynthesis())
+
+	// Changed while ignoring side effects
+	secret.Data["foo"] = []byte("baz")
+	require.NoError(t, cli.Update(ctx, secret))
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	comp.EnableIgnoreSideEffects()
+	require.NoError(t, cli.Update(ctx, comp))
+	assert.False(t, reconcile().ShouldForceResynthesis())
+
+	// Changed
+	delete(comp.Annotations, "eno.azure.io/ignore-side-effects")
+	require.NoError(t, cli.Update(ctx, comp))
+	assert.True(t, reconcile().ShouldForceResynthesis())
+}
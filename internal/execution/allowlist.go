@@ -0,0 +1,58 @@
+package execution
+
+import (
+	"fmt"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// checkAllowlist returns an error result for every synthesized resource that isn't allowed by the synthesizer's allowlist.
+func checkAllowlist(syn *apiv1.Synthesizer, rl *krmv1.ResourceList) []*krmv1.Result {
+	if syn.Spec.Allowlist == nil {
+		return nil
+	}
+
+	var results []*krmv1.Result
+	var denied int
+	for _, obj := range rl.Items {
+		if syn.Spec.Allowlist.Allows(targetGroupKind(obj), obj.GetNamespace(), obj.GetName()) {
+			continue
+		}
+
+		denied++
+		if denied > maxPolicyResults {
+			continue
+		}
+		results = append(results, &krmv1.Result{
+			Message:  fmt.Sprintf("resource %s is not allowed by the synthesizer's allowlist", describeResource(obj)),
+			Severity: krmv1.ResultSeverityError,
+			ResourceRef: &krmv1.ResultResourceRef{
+				APIVersion: obj.GetAPIVersion(),
+				Kind:       obj.GetKind(),
+				Name:       obj.GetName(),
+				Namespace:  obj.GetNamespace(),
+			},
+		})
+	}
+	if denied > maxPolicyResults {
+		results = append(results, &krmv1.Result{
+			Message:  fmt.Sprintf("%d more resources are not allowed", denied-maxPolicyResults),
+			Severity: krmv1.ResultSeverityError,
+		})
+	}
+	return results
+}
+
+// targetGroupKind returns the kind of the resource modified by the given object i.e. the target of patches.
+func targetGroupKind(obj *unstructured.Unstructured) schema.GroupKind {
+	if obj.GroupVersionKind() != patchGVK {
+		return obj.GroupVersionKind().GroupKind()
+	}
+	apiVersion, _, _ := unstructured.NestedString(obj.Object, "patch", "apiVersion")
+	kind, _, _ := unstructured.NestedString(obj.Object, "patch", "kind")
+	gv, _ := schema.ParseGroupVersion(apiVersion)
+	return schema.GroupKind{Group: gv.Group, Kind: kind}
+}
@@ -0,0 +1,112 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCheckAllowlist(t *testing.T) {
+	syn := &apiv1.Synthesizer{}
+	rl := &krmv1.ResourceList{Items: []*unstructured.Unstructured{
+		newTestObject("ConfigMap", "allowed"),
+		newTestObject("Secret", "denied-kind"),
+		{Object: map[string]any{
+			"apiVersion": "eno.azure.io/v1",
+			"kind":       "Patch",
+			"metadata":   map[string]any{"name": "denied-patch", "namespace": "default"},
+			"patch":      map[string]any{"apiVersion": "v1", "kind": "Secret", "ops": []any{}},
+		}},
+	}}
+	assert.Empty(t, checkAllowlist(syn, rl), "everything is allowed by default")
+
+	syn.Spec.Allowlist = &apiv1.ResourceAllowlist{Namespaces: []string{"default"}, Kinds: []apiv1.AllowedKind{{Kind: "ConfigMap"}}}
+	results := checkAllowlist(syn, rl)
+	require.Len(t, results, 2)
+	assert.Equal(t, `resource Secret "denied-kind" in namespace "default" is not allowed by the synthesizer's allowlist`, results[0].Message)
+	assert.Equal(t, krmv1.ResultSeverityError, results[0].Severity)
+	assert.Equal(t, `resource Patch "denied-patch" in namespace "default" is not allowed by the synthesizer's allowlist`, results[1].Message)
+}
+
+func TestCheckAllowlistLimit(t *testing.T) {
+	syn := &apiv1.Synthesizer{}
+	syn.Spec.Allowlist = &apiv1.ResourceAllowlist{Namespaces: []string{"other"}}
+
+	rl := &krmv1.ResourceList{}
+	for i := 0; i < maxPolicyResults+3; i++ {
+		rl.Items = append(rl.Items, newTestObject("ConfigMap", fmt.Sprintf("test-%d", i)))
+	}
+
+	results := checkAllowlist(syn, rl)
+	require.Len(t, results, maxPolicyResults+1)
+	assert.Equal(t, "3 more resources are not allowed", results[maxPolicyResults].Message)
+}
+
+func TestSynthesizeAllowlist(t *testing.T) {
+	ctx := context.Background()
+	cli := newPolicyTestClient(t)
+
+	syn := &apiv1.Synthesizer{}
+	syn.Name = "test-synth"
+	syn.Spec.Allowlist = &apiv1.ResourceAllowlist{Namespaces: []string{"other"}}
+	require.NoError(t, cli.Create(ctx, syn))
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Spec.Synthesizer.Name = syn.Name
+	require.NoError(t, cli.Create(ctx, comp))
+
+	comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "test-uuid"}
+	require.NoError(t, cli.Status().Update(ctx, comp))
+
+	e := &Executor{
+		Reader: cli,
+		Writer: cli,
+		Handler: func(ctx context.Context, s *apiv1.Synthesizer, rl *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+			return &krmv1.ResourceList{Items: []*unstructured.Unstructured{newTestObject("ConfigMap", "test")}}, nil
+		},
+	}
+	env := &Env{
+		CompositionName:      comp.Name,
+		CompositionNamespace: comp.Namespace,
+		SynthesisUUID:        comp.Status.CurrentSynthesis.UUID,
+	}
+	require.NoError(t, e.Synthesize(ctx, env))
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	synthesis := comp.Status.CurrentSynthesis
+	assert.NotNil(t, synthesis.Synthesized)
+	assert.True(t, synthesis.Failed())
+	assert.Empty(t, synthesis.ResourceSlices)
+	require.Len(t, synthesis.Results, 1)
+	assert.Contains(t, synthesis.Results[0].Message, "is not allowed by the synthesizer's allowlist")
+
+	// The allowlist is recorded on the slices of successful syntheses
+	syn.Spec.Allowlist.Namespaces = []string{"default"}
+	require.NoError(t, cli.Update(ctx, syn))
+
+	comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "test-uuid-2"}
+	require.NoError(t, cli.Status().Update(ctx, comp))
+	env.SynthesisUUID = comp.Status.CurrentSynthesis.UUID
+	require.NoError(t, e.Synthesize(ctx, env))
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	synthesis = comp.Status.CurrentSynthesis
+	assert.False(t, synthesis.Failed())
+	require.Len(t, synthesis.ResourceSlices, 1)
+
+	slice := &apiv1.ResourceSlice{}
+	slice.Name = synthesis.ResourceSlices[0].Name
+	slice.Namespace = comp.Namespace
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(slice), slice))
+	assert.Equal(t, syn.Spec.Allowlist, slice.Spec.Allowlist)
+	assert.Nil(t, slice.Spec.ServerSideApply)
+}
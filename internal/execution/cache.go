@@ -0,0 +1,95 @@
+package execution
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cacheable returns true when the previous synthesis's output could potentially be reused by the current synthesis.
+func cacheable(comp *apiv1.Composition) bool {
+	prev := comp.Status.PreviousSynthesis
+	return prev != nil && prev.InputHash != "" && prev.UUID != "" && prev.Synthesized != nil && !prev.Failed() && !comp.ResynthesisForced()
+}
+
+// reusePreviousSynthesis completes the current synthesis using the resource slices of the previous one if their input hashes match.
+// The current synthesis keeps the UUID it was dispatched with, and shares the previous synthesis's slices.
+func (e *Executor) reusePreviousSynthesis(ctx context.Context, env *Env, oldComp *apiv1.Composition, syn *apiv1.Synthesizer, inputs *synthesisInputs, hash string) (bool, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	var hit bool
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		hit = false
+		comp := &apiv1.Composition{}
+		err := e.Reader.Get(ctx, client.ObjectKeyFromObject(oldComp), comp)
+		if err != nil {
+			return err
+		}
+		if _, skip := skipSynthesis(comp, env); skip || !cacheable(comp) || comp.Status.PreviousSynthesis.InputHash != hash {
+			return nil
+		}
+
+		prev := comp.Status.PreviousSynthesis
+		now := metav1.Now()
+		current := comp.Status.CurrentSynthesis
+		current.Synthesized = &now
+		current.ResourceSlices = prev.ResourceSlices
+		current.ObservedSynthesizerGeneration = syn.Generation
+		inputs.record(current)
+		current.InputHash = hash
+		current.Results = prev.Results
+		current.CacheHit = true
+
+		err = e.Writer.Status().Update(ctx, comp)
+		if err != nil {
+			return err
+		}
+		hit = true
+		logger.V(0).Info("reused the resource slices of the previous synthesis because its inputs haven't changed", "previousSynthesisID", prev.UUID)
+		return nil
+	})
+	return hit, err
+}
+
+// inputHash returns a hash of everything that might influence the synthesizer's output.
+// Metadata that changes with every write to an input (resourceVersion, etc.) is not included.
+func inputHash(comp *apiv1.Composition, syn *apiv1.Synthesizer, in *synthesisInputs) (string, error) {
+	inputs := make([]map[string]any, len(in.resources.Items))
+	for i, item := range in.resources.Items {
+		obj := item.DeepCopy()
+		unstructured.RemoveNestedField(obj.Object, "metadata", "resourceVersion")
+		unstructured.RemoveNestedField(obj.Object, "metadata", "generation")
+		unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+		inputs[i] = obj.Object
+	}
+
+	fields := map[string]any{
+		"synthesizer":           syn.Name,
+		"synthesizerGeneration": syn.Generation,
+		"composition":           comp.Spec,
+		"inputs":                inputs,
+	}
+	if len(in.envRevisions) > 0 {
+		// The values are resolved by the kubelet, so the versions of the referenced objects stand in for them.
+		// The composition spec already identifies the object and key referenced by each env var.
+		fields["envRevisions"] = in.envRevisions
+	}
+	if len(in.policies) > 0 {
+		fields["policies"] = in.policies
+	}
+
+	js, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(js)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,170 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSynthesisCache(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiv1.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, corev1.SchemeBuilder.AddToScheme(scheme))
+
+	cli := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&apiv1.ResourceSlice{}, &apiv1.Composition{}).
+		Build()
+
+	input := &corev1.ConfigMap{}
+	input.Name = "test-input"
+	input.Namespace = "default"
+	input.Data = map[string]string{"foo": "bar"}
+	require.NoError(t, cli.Create(ctx, input))
+
+	syn := &apiv1.Synthesizer{}
+	syn.Name = "test-synth"
+	syn.Spec.Refs = []apiv1.Ref{{
+		Key:      "foo",
+		Resource: apiv1.ResourceRef{Kind: "ConfigMap", Version: "v1"},
+	}}
+	require.NoError(t, cli.Create(ctx, syn))
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Spec.Bindings = []apiv1.Binding{{
+		Key:      "foo",
+		Resource: apiv1.ResourceBinding{Name: input.Name, Namespace: input.Namespace},
+	}}
+	comp.Spec.Synthesizer.Name = syn.Name
+	require.NoError(t, cli.Create(ctx, comp))
+
+	comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "uuid-1"}
+	require.NoError(t, cli.Status().Update(ctx, comp))
+
+	var calls int
+	e := &Executor{
+		Reader: cli,
+		Writer: cli,
+		Handler: func(ctx context.Context, s *apiv1.Synthesizer, rl *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+			calls++
+			out := &unstructured.Unstructured{
+				Object: map[string]any{
+					"apiVersion": "v1",
+					"kind":       "ConfigMap",
+					"metadata": map[string]any{
+						"name":      "test",
+						"namespace": "default",
+					},
+					"data": rl.Items[0].Object["data"],
+				},
+			}
+			return &krmv1.ResourceList{Items: []*unstructured.Unstructured{out}}, nil
+		},
+	}
+	env := &Env{CompositionName: comp.Name, CompositionNamespace: comp.Namespace}
+
+	dispatch := func(uuid string) {
+		require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+		comp.Status.PreviousSynthesis = comp.Status.CurrentSynthesis
+		comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: uuid, ObservedCompositionGeneration: comp.Generation}
+		require.NoError(t, cli.Status().Update(ctx, comp))
+		env.SynthesisUUID = uuid
+	}
+
+	// Initial synthesis can't be cached
+	env.SynthesisUUID = "uuid-1"
+	require.NoError(t, e.Synthesize(ctx, env))
+	assert.Equal(t, 1, calls)
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	initial := comp.Status.CurrentSynthesis
+	assert.NotEmpty(t, initial.InputHash)
+	assert.False(t, initial.CacheHit)
+
+	// Bumping the input's resource version doesn't invalidate the cache
+	input.Labels = nil
+	require.NoError(t, cli.Update(ctx, input))
+	dispatch("uuid-2")
+
+	require.NoError(t, e.Synthesize(ctx, env))
+	assert.Equal(t, 1, calls)
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	current := comp.Status.CurrentSynthesis
+	assert.True(t, current.CacheHit)
+	assert.Equal(t, "uuid-2", current.UUID, "cache hits keep the dispatched UUID")
+	assert.Equal(t, initial.InputHash, current.InputHash)
+	assert.Equal(t, initial.ResourceSlices, current.ResourceSlices)
+	assert.NotNil(t, current.Synthesized)
+	assert.Equal(t, input.ResourceVersion, current.InputRevisions[0].ResourceVersion)
+
+	// Forced resynthesis bypasses the cache
+	comp.ForceResynthesis()
+	require.NoError(t, cli.Update(ctx, comp))
+	dispatch("uuid-3")
+
+	require.NoError(t, e.Synthesize(ctx, env))
+	assert.Equal(t, 2, calls)
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	assert.False(t, comp.Status.CurrentSynthesis.CacheHit)
+	assert.Equal(t, "uuid-3", comp.Status.CurrentSynthesis.UUID)
+	assert.Equal(t, initial.InputHash, comp.Status.CurrentSynthesis.InputHash)
+	assert.NotEqual(t, initial.ResourceSlices, comp.Status.CurrentSynthesis.ResourceSlices)
+
+	// Modifying the input's contents invalidates the cache
+	input.Data["foo"] = "baz"
+	require.NoError(t, cli.Update(ctx, input))
+	dispatch("uuid-4")
+
+	require.NoError(t, e.Synthesize(ctx, env))
+	assert.Equal(t, 3, calls)
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	assert.False(t, comp.Status.CurrentSynthesis.CacheHit)
+	assert.NotEqual(t, initial.InputHash, comp.Status.CurrentSynthesis.InputHash)
+
+	// Adding a policy invalidates the cache, since the previous output hasn't been evaluated against it
+	policy := &apiv1.Policy{}
+	policy.Name = "test-policy"
+	policy.Spec.Rules = []apiv1.PolicyRule{{Name: "test-rule", Expression: "true"}}
+	require.NoError(t, cli.Create(ctx, policy))
+	dispatch("uuid-5")
+
+	require.NoError(t, e.Synthesize(ctx, env))
+	assert.Equal(t, 4, calls)
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	assert.False(t, comp.Status.CurrentSynthesis.CacheHit)
+}
+
+func TestInputHashEnv(t *testing.T) {
+	comp := &apiv1.Composition{}
+	syn := &apiv1.Synthesizer{}
+	newInputs := func(rv string) *synthesisInputs {
+		return &synthesisInputs{
+			resources:    &krmv1.ResourceList{},
+			envRevisions: []apiv1.InputRevisions{{Key: "PASSWORD", ResourceVersion: rv}},
+		}
+	}
+
+	hash, err := inputHash(comp, syn, newInputs("1"))
+	require.NoError(t, err)
+
+	sameRev, err := inputHash(comp, syn, newInputs("1"))
+	require.NoError(t, err)
+	assert.Equal(t, hash, sameRev)
+
+	// The versions of referenced objects are hashed
+	newRev, err := inputHash(comp, syn, newInputs("2"))
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, newRev)
+}
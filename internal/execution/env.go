@@ -0,0 +1,97 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+// reservedEnv are set on synthesizer pods by the controller and can't be overridden by compositions.
+var reservedEnv = []string{"COMPOSITION_NAME", "COMPOSITION_NAMESPACE", "SYNTHESIS_UUID", "SYNTHESIS_ATTEMPT", "PIPELINE_STEP"}
+
+// synthesisInputs is everything passed to the synthesizer, along with the versions of the resources it was read from.
+type synthesisInputs struct {
+	resources    *krmv1.ResourceList
+	revisions    []apiv1.InputRevisions
+	envRevisions []apiv1.InputRevisions
+	policies     []string // name=resourceVersion of every policy the output will be evaluated against
+
+	// steps and results of the pipeline steps that have already been executed
+	steps   []apiv1.SynthesisStep
+	results []*krmv1.Result
+}
+
+// record sets the input revisions (and the pipeline steps executed so far) on the given synthesis.
+func (s *synthesisInputs) record(synthesis *apiv1.Synthesis) {
+	synthesis.InputRevisions = s.revisions
+	synthesis.EnvRevisions = s.envRevisions
+	synthesis.Steps = s.steps
+}
+
+func (e *Executor) buildInputs(ctx context.Context, comp *apiv1.Composition, syn *apiv1.Synthesizer) (*synthesisInputs, error) {
+	rl, revs, err := e.buildPodInput(ctx, comp, syn)
+	if err != nil {
+		return nil, fmt.Errorf("building synthesizer input: %w", err)
+	}
+
+	envRevs, err := e.resolveEnvRevisions(ctx, comp)
+	if err != nil {
+		return nil, fmt.Errorf("resolving synthesis env: %w", err)
+	}
+
+	// Policies don't influence the synthesizer's output, but a synthesis can't be reused once they've changed
+	policies := &apiv1.PolicyList{}
+	err = e.Reader.List(ctx, policies)
+	if err != nil {
+		return nil, fmt.Errorf("listing policies: %w", err)
+	}
+	policyVersions := make([]string, len(policies.Items))
+	for i, policy := range policies.Items {
+		policyVersions[i] = policy.Name + "=" + policy.ResourceVersion
+	}
+	slices.Sort(policyVersions)
+
+	return &synthesisInputs{resources: rl, revisions: revs, envRevisions: envRevs, policies: policyVersions}, nil
+}
+
+// resolveEnvRevisions returns the versions of the Secrets and ConfigMaps referenced by synthesisEnv.
+// Their values are resolved by the kubelet when the synthesizer pod starts, so only metadata is read here.
+func (e *Executor) resolveEnvRevisions(ctx context.Context, comp *apiv1.Composition) ([]apiv1.InputRevisions, error) {
+	var revs []apiv1.InputRevisions
+	for _, ev := range comp.Spec.SynthesisEnv {
+		if ev.ValueFrom == nil || slices.Contains(reservedEnv, ev.Name) {
+			continue
+		}
+
+		meta := &metav1.PartialObjectMetadata{}
+		var optional bool
+		switch src := ev.ValueFrom; {
+		case src.SecretKeyRef != nil:
+			meta.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+			meta.Name, optional = src.SecretKeyRef.Name, ptr.Deref(src.SecretKeyRef.Optional, false)
+		case src.ConfigMapKeyRef != nil:
+			meta.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+			meta.Name, optional = src.ConfigMapKeyRef.Name, ptr.Deref(src.ConfigMapKeyRef.Optional, false)
+		default:
+			continue
+		}
+
+		err := e.Reader.Get(ctx, types.NamespacedName{Name: meta.Name, Namespace: comp.Namespace}, meta)
+		if errors.IsNotFound(err) && optional {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting %q for env var %q: %w", meta.Name, ev.Name, err)
+		}
+		revs = append(revs, apiv1.InputRevisions{Key: ev.Name, ResourceVersion: meta.ResourceVersion})
+	}
+	return revs, nil
+}
@@ -0,0 +1,99 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSynthesisEnvRevisions(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiv1.SchemeBuilder.AddToScheme(scheme))
+	require.NoError(t, corev1.SchemeBuilder.AddToScheme(scheme))
+
+	secret := &corev1.Secret{}
+	secret.Name = "test-secret"
+	secret.Namespace = "default"
+	secret.Data = map[string][]byte{"password": []byte("hunter2")}
+
+	cm := &corev1.ConfigMap{}
+	cm.Name = "test-cm"
+	cm.Namespace = "default"
+	cm.Data = map[string]string{"region": "westus"}
+
+	cli := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(secret, cm).
+		WithStatusSubresource(&apiv1.ResourceSlice{}, &apiv1.Composition{}).
+		Build()
+
+	syn := &apiv1.Synthesizer{}
+	syn.Name = "test-synth"
+	require.NoError(t, cli.Create(ctx, syn))
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Spec.Synthesizer.Name = syn.Name
+	comp.Spec.SynthesisEnv = []apiv1.EnvVar{
+		{Name: "LITERAL", Value: "foo"},
+		{Name: "PASSWORD", ValueFrom: &apiv1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+			Key:                  "password",
+		}}},
+		{Name: "REGION", ValueFrom: &apiv1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: cm.Name},
+			Key:                  "region",
+		}}},
+		{Name: "MISSING_KEY", ValueFrom: &apiv1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: cm.Name},
+			Key:                  "nope",
+			Optional:             ptr.To(true),
+		}}},
+		{Name: "MISSING_OBJECT", ValueFrom: &apiv1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "nope"},
+			Key:                  "nope",
+			Optional:             ptr.To(true),
+		}}},
+		{Name: "SYNTHESIS_UUID", ValueFrom: &apiv1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+			Key:                  "password",
+		}}},
+	}
+	require.NoError(t, cli.Create(ctx, comp))
+
+	comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "test-uuid"}
+	require.NoError(t, cli.Status().Update(ctx, comp))
+
+	e := &Executor{
+		Reader: cli,
+		Writer: cli,
+		Handler: func(ctx context.Context, s *apiv1.Synthesizer, rl *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+			return &krmv1.ResourceList{}, nil
+		},
+	}
+
+	require.NoError(t, e.Synthesize(ctx, &Env{CompositionName: comp.Name, CompositionNamespace: comp.Namespace, SynthesisUUID: "test-uuid"}))
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	assert.Equal(t, []apiv1.InputRevisions{
+		{Key: "PASSWORD", ResourceVersion: secret.ResourceVersion},
+		{Key: "REGION", ResourceVersion: cm.ResourceVersion},
+		{Key: "MISSING_KEY", ResourceVersion: cm.ResourceVersion},
+	}, comp.Status.CurrentSynthesis.EnvRevisions)
+
+	// Required references must exist
+	comp.Spec.SynthesisEnv[4].ValueFrom.SecretKeyRef.Optional = nil
+	_, err := e.resolveEnvRevisions(ctx, comp)
+	assert.ErrorContains(t, err, `getting "nope" for env var "MISSING_OBJECT"`)
+}
@@ -2,6 +2,7 @@ package execution
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -24,6 +25,12 @@ type Executor struct {
 	Reader  client.Reader
 	Writer  client.Client
 	Handler SynthesizerHandle
+
+	// PipelineDir is used to pass the output of each step to the next when the synthesizer declares functions.
+	PipelineDir string
+
+	// Schemas are used to validate synthesized resources when the synthesizer's validation mode is "Schema".
+	Schemas resource.SchemaGetter
 }
 
 func (e *Executor) Synthesize(ctx context.Context, env *Env) error {
@@ -41,29 +48,114 @@ func (e *Executor) Synthesize(ctx context.Context, env *Env) error {
 		return nil
 	}
 
-	syn := &apiv1.Synthesizer{}
-	syn.Name = comp.Spec.Synthesizer.Name
-	err = e.Reader.Get(ctx, client.ObjectKeyFromObject(syn), syn)
+	syn, err := e.getSynthesizer(ctx, comp)
 	if err != nil {
 		return fmt.Errorf("fetching synthesizer: %w", err)
 	}
 
-	input, revs, err := e.buildPodInput(ctx, comp, syn)
-	if err != nil {
-		return fmt.Errorf("building synthesizer input: %w", err)
+	steps := pipelineSteps(syn)
+	if env.PipelineStep < 0 || env.PipelineStep >= len(steps) {
+		return fmt.Errorf("pipeline step %d is out of range - the synthesizer has %d steps", env.PipelineStep, len(steps))
+	}
+	step := steps[env.PipelineStep]
+
+	var inputs *synthesisInputs
+	var hash string
+	if env.PipelineStep > 0 {
+		inputs, hash, err = e.readPipelineState(env.PipelineStep - 1)
+		if err != nil {
+			return fmt.Errorf("reading the output of the previous pipeline step: %w", err)
+		}
+	} else {
+		inputs, err = e.buildInputs(ctx, comp, syn)
+		if err != nil {
+			return err
+		}
+
+		hash, err = inputHash(comp, syn, inputs)
+		if err != nil {
+			return fmt.Errorf("hashing synthesizer input: %w", err)
+		}
+		if cacheable(comp) && comp.Status.PreviousSynthesis.InputHash == hash {
+			hit, err := e.reusePreviousSynthesis(ctx, env, comp, syn, inputs, hash)
+			if err != nil || hit {
+				return err
+			}
+		}
 	}
 
-	output, err := e.Handler(ctx, syn, input)
+	start := time.Now()
+	output, err := e.Handler(ctx, step.synthesizer, inputs.resources)
+	if err == nil {
+		err = retryableResult(output)
+	}
 	if err != nil {
-		return fmt.Errorf("executing synthesizer: %w", err)
+		return e.handleFailure(ctx, env, comp, syn, inputs, step.wrapError(err))
 	}
 
-	sliceRefs, err := e.writeSlices(ctx, comp, output)
+	if len(steps) > 1 {
+		latency := time.Since(start)
+		logger.V(0).Info("executed pipeline step", "step", step.name, "latency", latency.Milliseconds())
+
+		// The pipeline stops early when a step fails, otherwise the output is passed to the next step
+		inputs = inputs.next(step.name, output, latency)
+		if env.PipelineStep < len(steps)-1 && !hasErrorResult(output) {
+			return e.writePipelineState(env.PipelineStep, inputs, hash)
+		}
+		output = inputs.resources
+		output.Results = inputs.results
+	}
+
+	// Resources that aren't allowed by the synthesizer or violate deny policies fail the synthesis without being written to resource slices
+	if !hasErrorResult(output) {
+		results := checkAllowlist(syn, output)
+		if len(results) == 0 {
+			results, err = e.evaluatePolicies(ctx, output)
+			if err != nil {
+				return fmt.Errorf("evaluating policies: %w", err) // not the synthesizer's fault, so it doesn't count as a failure
+			}
+		}
+		output.Results = append(output.Results, results...)
+		if hasErrorResult(output) {
+			return e.updateComposition(ctx, env, comp, syn, nil, inputs, hash, output)
+		}
+	}
+
+	// Invalid resources fail the synthesis without being written to resource slices
+	if syn.Spec.Validation != "" && !hasErrorResult(output) {
+		results, err := e.validateOutput(ctx, syn, output)
+		if err != nil {
+			return fmt.Errorf("validating output: %w", err) // not the synthesizer's fault, so it doesn't count as a failure
+		}
+		if len(results) > 0 {
+			output.Results = append(output.Results, results...)
+			return e.updateComposition(ctx, env, comp, syn, nil, inputs, hash, output)
+		}
+	}
+
+	sliceRefs, err := e.writeSlices(ctx, comp, syn, output)
 	if err != nil {
 		return err
 	}
 
-	return e.updateComposition(ctx, env, comp, syn, sliceRefs, revs, output)
+	return e.updateComposition(ctx, env, comp, syn, sliceRefs, inputs, hash, output)
+}
+
+// getSynthesizer returns the synthesizer referenced by the composition, or the pinned revision of it.
+func (e *Executor) getSynthesizer(ctx context.Context, comp *apiv1.Composition) (*apiv1.Synthesizer, error) {
+	ref := comp.Spec.Synthesizer
+	if ref.Revision == nil {
+		syn := &apiv1.Synthesizer{}
+		syn.Name = ref.Name
+		return syn, e.Reader.Get(ctx, client.ObjectKeyFromObject(syn), syn)
+	}
+
+	rev := &apiv1.SynthesizerRevision{}
+	rev.Name = apiv1.SynthesizerRevisionName(ref.Name, *ref.Revision)
+	if err := e.Reader.Get(ctx, client.ObjectKeyFromObject(rev), rev); err != nil {
+		return nil, fmt.Errorf("fetching synthesizer revision: %w", err)
+	}
+	return rev.Synthesizer(), nil
 }
 
 func (e *Executor) buildPodInput(ctx context.Context, comp *apiv1.Composition, syn *apiv1.Synthesizer) (*krmv1.ResourceList, []apiv1.InputRevisions, error) {
@@ -78,6 +170,14 @@ func (e *Executor) buildPodInput(ctx context.Context, comp *apiv1.Composition, s
 		Kind:       krmv1.ResourceListKind,
 		APIVersion: krmv1.SchemeGroupVersion.String(),
 	}
+	if comp.Spec.Values != nil {
+		fc, err := newFunctionConfig(comp)
+		if err != nil {
+			return nil, nil, fmt.Errorf("building function config: %w", err)
+		}
+		rl.FunctionConfig = fc
+	}
+
 	revs := []apiv1.InputRevisions{}
 	for _, r := range syn.Spec.Refs {
 		key := r.Key
@@ -112,7 +212,23 @@ func (e *Executor) buildPodInput(ctx context.Context, comp *apiv1.Composition, s
 	return rl, revs, nil
 }
 
-func (e *Executor) writeSlices(ctx context.Context, comp *apiv1.Composition, rl *krmv1.ResourceList) ([]*apiv1.ResourceSliceRef, error) {
+// newFunctionConfig wraps the composition's values in an object that can be passed to the synthesizer as its functionConfig.
+func newFunctionConfig(comp *apiv1.Composition) (*unstructured.Unstructured, error) {
+	values := map[string]any{}
+	err := json.Unmarshal(comp.Spec.Values.Raw, &values)
+	if err != nil {
+		return nil, err
+	}
+
+	fc := &unstructured.Unstructured{Object: map[string]any{"values": values}}
+	fc.SetAPIVersion(apiv1.SchemeGroupVersion.String())
+	fc.SetKind("CompositionValues")
+	fc.SetName(comp.Name)
+	fc.SetNamespace(comp.Namespace)
+	return fc, nil
+}
+
+func (e *Executor) writeSlices(ctx context.Context, comp *apiv1.Composition, syn *apiv1.Synthesizer, rl *krmv1.ResourceList) ([]*apiv1.ResourceSliceRef, error) {
 	logger := logr.FromContextOrDiscard(ctx)
 
 	previous, err := e.fetchPreviousSlices(ctx, comp)
@@ -128,6 +244,9 @@ func (e *Executor) writeSlices(ctx context.Context, comp *apiv1.Composition, rl
 	sliceRefs := make([]*apiv1.ResourceSliceRef, len(slices))
 	for i, slice := range slices {
 		start := time.Now()
+		// The reconciler uses the synthesizer's configuration as of this synthesis
+		slice.Spec.Allowlist = syn.Spec.Allowlist.DeepCopy()
+		slice.Spec.ServerSideApply = syn.Spec.ServerSideApply.DeepCopy()
 
 		err = e.writeResourceSlice(ctx, slice)
 		if err != nil {
@@ -183,7 +302,7 @@ func (e *Executor) writeResourceSlice(ctx context.Context, slice *apiv1.Resource
 	})
 }
 
-func (e *Executor) updateComposition(ctx context.Context, env *Env, oldComp *apiv1.Composition, syn *apiv1.Synthesizer, refs []*apiv1.ResourceSliceRef, revs []apiv1.InputRevisions, rl *krmv1.ResourceList) error {
+func (e *Executor) updateComposition(ctx context.Context, env *Env, oldComp *apiv1.Composition, syn *apiv1.Synthesizer, refs []*apiv1.ResourceSliceRef, inputs *synthesisInputs, hash string, rl *krmv1.ResourceList) error {
 	logger := logr.FromContextOrDiscard(ctx)
 	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		comp := &apiv1.Composition{}
@@ -200,7 +319,9 @@ func (e *Executor) updateComposition(ctx context.Context, env *Env, oldComp *api
 		comp.Status.CurrentSynthesis.Synthesized = &now
 		comp.Status.CurrentSynthesis.ResourceSlices = refs
 		comp.Status.CurrentSynthesis.ObservedSynthesizerGeneration = syn.Generation
-		comp.Status.CurrentSynthesis.InputRevisions = revs
+		inputs.record(comp.Status.CurrentSynthesis)
+		comp.Status.CurrentSynthesis.InputHash = hash
+		comp.Status.CurrentSynthesis.Results = nil // clear failures from previous attempts
 		for _, result := range rl.Results {
 			comp.Status.CurrentSynthesis.Results = append(comp.Status.CurrentSynthesis.Results, apiv1.Result{
 				Message:  result.Message,
@@ -219,6 +340,55 @@ func (e *Executor) updateComposition(ctx context.Context, env *Env, oldComp *api
 	})
 }
 
+// handleFailure records a failed execution of the synthesizer as a result of the current synthesis.
+// The synthesis is completed as failed when the failure is terminal or the synthesis is on the last attempt allowed by the synthesizer.
+// Otherwise the error is returned so the synthesis can be retried, and the result is replaced once an attempt succeeds.
+func (e *Executor) handleFailure(ctx context.Context, env *Env, oldComp *apiv1.Composition, syn *apiv1.Synthesizer, inputs *synthesisInputs, synthErr error) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	terminal := isTerminal(synthErr)
+	msg := failureMessage(synthErr)
+	if env.SynthesisAttempt > 0 {
+		msg = fmt.Sprintf("%s (attempt %d)", msg, env.SynthesisAttempt)
+	}
+
+	var failed bool
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		failed = false
+		comp := &apiv1.Composition{}
+		err := e.Reader.Get(ctx, client.ObjectKeyFromObject(oldComp), comp)
+		if err != nil {
+			return err
+		}
+		if _, skip := skipSynthesis(comp, env); skip {
+			return nil
+		}
+
+		synthesis := comp.Status.CurrentSynthesis
+		synthesis.Failures++
+		synthesis.Results = []apiv1.Result{{Message: msg, Severity: krmv1.ResultSeverityError}}
+		// The attempt budget is shared with the pod lifecycle controller, which counts attempts (pods or service requests) rather than failures.
+		// So failures of a retried synthesizer process that's running in the same pod don't use up the budget.
+		attempt := max(env.SynthesisAttempt, synthesis.Attempts)
+		if terminal || (syn.Spec.MaxAttempts != nil && attempt >= *syn.Spec.MaxAttempts) {
+			now := metav1.Now()
+			synthesis.Synthesized = &now
+			synthesis.ObservedSynthesizerGeneration = syn.Generation
+			inputs.record(synthesis)
+			failed = true
+		}
+		return e.Writer.Status().Update(ctx, comp)
+	})
+	if err != nil {
+		logger.Error(err, "unable to record synthesizer failure in composition status")
+		return synthErr
+	}
+	if failed {
+		logger.V(0).Info("synthesis failed - it will not be retried", "error", synthErr.Error(), "terminal", terminal)
+		return nil
+	}
+	return synthErr
+}
+
 func skipSynthesis(comp *apiv1.Composition, env *Env) (string, bool) {
 	synthesis := comp.Status.CurrentSynthesis
 	if synthesis == nil {
@@ -2,6 +2,7 @@ package execution
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -188,6 +189,60 @@ func TestWithInputs(t *testing.T) {
 	assert.NotNil(t, comp.Status.CurrentSynthesis.Synthesized)
 }
 
+func TestWithValues(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiv1.SchemeBuilder.AddToScheme(scheme))
+
+	cli := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&apiv1.ResourceSlice{}, &apiv1.Composition{}).
+		Build()
+
+	syn := &apiv1.Synthesizer{}
+	syn.Name = "test-synth"
+	err := cli.Create(ctx, syn)
+	require.NoError(t, err)
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Spec.Synthesizer.Name = syn.Name
+	comp.Spec.Values = &runtime.RawExtension{Raw: []byte(`{"replicas": 3, "labels": {"foo": "bar"}}`)}
+	err = cli.Create(ctx, comp)
+	require.NoError(t, err)
+
+	comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "test-uuid"}
+	err = cli.Status().Update(ctx, comp)
+	require.NoError(t, err)
+
+	e := &Executor{
+		Reader: cli,
+		Writer: cli,
+		Handler: func(ctx context.Context, s *apiv1.Synthesizer, rl *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+			require.NotNil(t, rl.FunctionConfig)
+			assert.Equal(t, "eno.azure.io/v1", rl.FunctionConfig.GetAPIVersion())
+			assert.Equal(t, "CompositionValues", rl.FunctionConfig.GetKind())
+			assert.Equal(t, "test-comp", rl.FunctionConfig.GetName())
+			assert.Equal(t, "default", rl.FunctionConfig.GetNamespace())
+			assert.Equal(t, map[string]any{"replicas": float64(3), "labels": map[string]any{"foo": "bar"}}, rl.FunctionConfig.Object["values"])
+			return &krmv1.ResourceList{}, nil
+		},
+	}
+	env := &Env{
+		CompositionName:      comp.Name,
+		CompositionNamespace: comp.Namespace,
+		SynthesisUUID:        comp.Status.CurrentSynthesis.UUID,
+	}
+
+	err = e.Synthesize(ctx, env)
+	require.NoError(t, err)
+
+	err = cli.Get(ctx, client.ObjectKeyFromObject(comp), comp)
+	require.NoError(t, err)
+	assert.NotNil(t, comp.Status.CurrentSynthesis.Synthesized)
+}
+
 func TestWithVersionedInput(t *testing.T) {
 	ctx := context.Background()
 	scheme := runtime.NewScheme()
@@ -402,3 +457,244 @@ func TestCompletionMismatchDuringSynthesis(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, originalSynthTime, *comp.Status.CurrentSynthesis.Synthesized)
 }
+
+func TestPinnedRevision(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiv1.SchemeBuilder.AddToScheme(scheme))
+
+	cli := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&apiv1.ResourceSlice{}, &apiv1.Composition{}).
+		Build()
+
+	syn := &apiv1.Synthesizer{}
+	syn.Name = "test-synth"
+	syn.Generation = 3
+	syn.Spec.Image = "new-image"
+	require.NoError(t, cli.Create(ctx, syn))
+
+	rev := &apiv1.SynthesizerRevision{}
+	rev.Name = apiv1.SynthesizerRevisionName(syn.Name, 2)
+	rev.Spec.Synthesizer = syn.Name
+	rev.Spec.Generation = 2
+	rev.Spec.SynthesizerSpec.Image = "old-image"
+	require.NoError(t, cli.Create(ctx, rev))
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Spec.Synthesizer.Name = syn.Name
+	comp.Spec.Synthesizer.Revision = ptr.To[int64](2)
+	require.NoError(t, cli.Create(ctx, comp))
+
+	comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "test-uuid"}
+	require.NoError(t, cli.Status().Update(ctx, comp))
+
+	var image string
+	e := &Executor{
+		Reader: cli,
+		Writer: cli,
+		Handler: func(ctx context.Context, s *apiv1.Synthesizer, rl *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+			image = s.Spec.Image
+			return &krmv1.ResourceList{}, nil
+		},
+	}
+	env := &Env{
+		CompositionName:      comp.Name,
+		CompositionNamespace: comp.Namespace,
+		SynthesisUUID:        comp.Status.CurrentSynthesis.UUID,
+	}
+	require.NoError(t, e.Synthesize(ctx, env))
+	assert.Equal(t, "old-image", image)
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	assert.Equal(t, int64(2), comp.Status.CurrentSynthesis.ObservedSynthesizerGeneration)
+}
+
+func TestSynthesizerFailure(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiv1.SchemeBuilder.AddToScheme(scheme))
+
+	cli := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&apiv1.ResourceSlice{}, &apiv1.Composition{}).
+		Build()
+
+	syn := &apiv1.Synthesizer{}
+	syn.Name = "test-synth"
+	err := cli.Create(ctx, syn)
+	require.NoError(t, err)
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Spec.Synthesizer.Name = syn.Name
+	err = cli.Create(ctx, comp)
+	require.NoError(t, err)
+
+	comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "test-uuid", Attempts: 2}
+	err = cli.Status().Update(ctx, comp)
+	require.NoError(t, err)
+
+	fail := true
+	e := &Executor{
+		Reader: cli,
+		Writer: cli,
+		Handler: func(ctx context.Context, s *apiv1.Synthesizer, rl *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+			if fail {
+				return nil, &ExecError{ExitCode: 1, Stderr: "bad input", err: errors.New("exit status 1")}
+			}
+			return &krmv1.ResourceList{}, nil
+		},
+	}
+	env := &Env{
+		CompositionName:      comp.Name,
+		CompositionNamespace: comp.Namespace,
+		SynthesisUUID:        comp.Status.CurrentSynthesis.UUID,
+		SynthesisAttempt:     2,
+	}
+
+	// Failures are surfaced in the status without completing the synthesis
+	err = e.Synthesize(ctx, env)
+	require.EqualError(t, err, "executing synthesizer: exit status 1")
+
+	err = cli.Get(ctx, client.ObjectKeyFromObject(comp), comp)
+	require.NoError(t, err)
+	assert.Nil(t, comp.Status.CurrentSynthesis.Synthesized)
+	assert.Equal(t, []apiv1.Result{{
+		Message:  "executing synthesizer: exit status 1: bad input (attempt 2)",
+		Severity: krmv1.ResultSeverityError,
+	}}, comp.Status.CurrentSynthesis.Results)
+
+	// A successful retry replaces the failure
+	fail = false
+	env.SynthesisAttempt = 3
+	comp.Status.CurrentSynthesis.Attempts = 3
+	err = cli.Status().Update(ctx, comp)
+	require.NoError(t, err)
+
+	err = e.Synthesize(ctx, env)
+	require.NoError(t, err)
+
+	err = cli.Get(ctx, client.ObjectKeyFromObject(comp), comp)
+	require.NoError(t, err)
+	assert.NotNil(t, comp.Status.CurrentSynthesis.Synthesized)
+	assert.Empty(t, comp.Status.CurrentSynthesis.Results)
+	assert.False(t, comp.Status.CurrentSynthesis.Failed())
+}
+
+func TestSynthesizerFailureClassification(t *testing.T) {
+	tests := []struct {
+		Name        string
+		MaxAttempts *int
+		Output      *krmv1.ResourceList
+		Err         error
+		Restarts    bool   // failures happen within the same attempt e.g. the process is restarted in the same pod
+		Failed      []bool // whether the synthesis has failed after each failure
+	}{
+		{
+			Name:   "transient",
+			Err:    &ExecError{ExitCode: 1, err: errors.New("exit status 1")},
+			Failed: []bool{false, false, false},
+		},
+		{
+			Name:   "terminal",
+			Err:    &ExecError{ExitCode: 78, Stderr: "invalid config", err: errors.New("exit status 78")},
+			Failed: []bool{true},
+		},
+		{
+			Name:        "transient with budget",
+			MaxAttempts: ptr.To(2),
+			Err:         &ExecError{ExitCode: 1, err: errors.New("exit status 1")},
+			Failed:      []bool{false, true},
+		},
+		{
+			Name:        "transient restarts with budget",
+			MaxAttempts: ptr.To(2),
+			Err:         &ExecError{ExitCode: 1, err: errors.New("exit status 1")},
+			Restarts:    true,
+			Failed:      []bool{false, false, false},
+		},
+		{
+			Name:   "retryable result",
+			Output: &krmv1.ResourceList{Results: []*krmv1.Result{{Message: "try again", Severity: "error", Tags: map[string]string{"eno.azure.io/retry": "true"}}}},
+			Failed: []bool{false, false},
+		},
+		{
+			Name:        "retryable result with budget",
+			MaxAttempts: ptr.To(1),
+			Output:      &krmv1.ResourceList{Results: []*krmv1.Result{{Message: "try again", Severity: "error", Tags: map[string]string{"eno.azure.io/retry": "true"}}}},
+			Failed:      []bool{true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			require.NoError(t, apiv1.SchemeBuilder.AddToScheme(scheme))
+
+			cli := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithStatusSubresource(&apiv1.ResourceSlice{}, &apiv1.Composition{}).
+				Build()
+
+			syn := &apiv1.Synthesizer{}
+			syn.Name = "test-synth"
+			syn.Spec.MaxAttempts = tc.MaxAttempts
+			require.NoError(t, cli.Create(ctx, syn))
+
+			comp := &apiv1.Composition{}
+			comp.Name = "test-comp"
+			comp.Namespace = "default"
+			comp.Spec.Synthesizer.Name = syn.Name
+			require.NoError(t, cli.Create(ctx, comp))
+
+			comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "test-uuid"}
+			require.NoError(t, cli.Status().Update(ctx, comp))
+
+			e := &Executor{
+				Reader: cli,
+				Writer: cli,
+				Handler: func(ctx context.Context, s *apiv1.Synthesizer, rl *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+					return tc.Output, tc.Err
+				},
+			}
+			env := &Env{
+				CompositionName:      comp.Name,
+				CompositionNamespace: comp.Namespace,
+				SynthesisUUID:        comp.Status.CurrentSynthesis.UUID,
+			}
+
+			for i, failed := range tc.Failed {
+				env.SynthesisAttempt = i + 1
+				if tc.Restarts {
+					env.SynthesisAttempt = 1
+				}
+				err := e.Synthesize(ctx, env)
+				if failed {
+					require.NoError(t, err)
+				} else {
+					require.Error(t, err)
+				}
+
+				require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+				assert.Equal(t, i+1, comp.Status.CurrentSynthesis.Failures)
+				assert.True(t, comp.Status.CurrentSynthesis.Failed())
+				assert.Equal(t, failed, comp.Status.CurrentSynthesis.Synthesized != nil, "attempt %d", i+1)
+			}
+		})
+	}
+}
+
+// newTestObject returns a resource of the given kind in the default namespace, with "bar" as the value of its "foo" key.
+func newTestObject(kind, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       kind,
+		"metadata":   map[string]any{"name": name, "namespace": "default"},
+		"data":       map[string]any{"foo": "bar"},
+	}}
+}
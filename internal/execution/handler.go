@@ -4,11 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"os/exec"
 	"strconv"
 
 	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/Azure/eno/pkg/function"
 	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
 )
 
@@ -17,24 +22,63 @@ type Env struct {
 	CompositionNamespace string
 	SynthesisUUID        string
 	SynthesisAttempt     int
+	PipelineStep         int // index of the pipeline step to execute, 0 is the synthesizer's own command
 }
 
 func LoadEnv() *Env {
 	attempt, _ := strconv.Atoi(os.Getenv("SYNTHESIS_ATTEMPT"))
+	step, _ := strconv.Atoi(os.Getenv("PIPELINE_STEP"))
 	return &Env{
 		CompositionName:      os.Getenv("COMPOSITION_NAME"),
 		CompositionNamespace: os.Getenv("COMPOSITION_NAMESPACE"),
 		SynthesisUUID:        os.Getenv("SYNTHESIS_UUID"),
 		SynthesisAttempt:     attempt,
+		PipelineStep:         step,
 	}
 }
 
 type SynthesizerHandle func(context.Context, *apiv1.Synthesizer, *krmv1.ResourceList) (*krmv1.ResourceList, error)
 
+// ExecError is returned by the exec handler when the synthesizer process exits unsuccessfully.
+type ExecError struct {
+	ExitCode int    // -1 if the process was terminated by a signal
+	Stderr   string // the last maxStderrTailBytes written by the process
+	err      error
+}
+
+func (e *ExecError) Error() string { return e.err.Error() }
+func (e *ExecError) Unwrap() error { return e.err }
+
+// isTerminal returns true when the synthesizer signaled that retrying won't resolve the given error.
+func isTerminal(err error) bool {
+	execErr := &ExecError{}
+	return errors.As(err, &execErr) && execErr.ExitCode == function.TerminalExitCode
+}
+
+// retryableResult returns an error for the first error result that is tagged as retryable.
+func retryableResult(rl *krmv1.ResourceList) error {
+	for _, result := range rl.Results {
+		if result.Severity == krmv1.ResultSeverityError && result.Tags[function.RetryTag] == "true" {
+			return errors.New(result.Message)
+		}
+	}
+	return nil
+}
+
+// failureMessage describes a synthesizer error, including the tail of its stderr when available.
+func failureMessage(err error) string {
+	if execErr := (&ExecError{}); errors.As(err, &execErr) && execErr.Stderr != "" {
+		return fmt.Sprintf("%s: %s", err, execErr.Stderr)
+	}
+	return err.Error()
+}
+
+// NewExecHandler returns a handler that runs the synthesizer's command with the input ResourceList on stdin.
+// Stdout can hold either a ResourceList or a stream of objects, in JSON or YAML.
 func NewExecHandler() SynthesizerHandle {
 	return func(ctx context.Context, s *apiv1.Synthesizer, rl *krmv1.ResourceList) (*krmv1.ResourceList, error) {
 		stdin := &bytes.Buffer{}
-		stdout := &bytes.Buffer{}
+		stdout := &limitedBuffer{limit: maxStdoutBytes}
 
 		err := json.NewEncoder(stdin).Encode(rl)
 		if err != nil {
@@ -52,21 +96,82 @@ func NewExecHandler() SynthesizerHandle {
 			defer cancel()
 		}
 
+		stderr := &tailBuffer{limit: maxStderrTailBytes}
 		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
 		cmd.Stdin = stdin
-		cmd.Stderr = os.Stdout // logger uses stderr, so use stdout to avoid race condition
+		cmd.Stderr = io.MultiWriter(os.Stdout, stderr) // logger uses stderr, so use stdout to avoid race condition
 		cmd.Stdout = stdout
 		err = cmd.Run()
+		if exitErr := (&exec.ExitError{}); errors.As(err, &exitErr) {
+			return nil, &ExecError{ExitCode: exitErr.ExitCode(), Stderr: stderr.String(), err: err}
+		}
 		if err != nil {
 			return nil, err
 		}
 
-		output := &krmv1.ResourceList{}
-		err = json.NewDecoder(stdout).Decode(output)
+		// Malformed output is reported as a result since retrying won't fix it
+		if stdout.truncated {
+			return outputErrorResult(fmt.Errorf("stdout exceeded %d bytes", maxStdoutBytes)), nil
+		}
+		output, err := decodeOutput(stdout)
+		if err != nil {
+			return outputErrorResult(err), nil
+		}
+
+		return output, nil
+	}
+}
+
+// NewServiceHandler returns a handler that calls synthesizers backed by a long-running HTTP service (spec.service).
+// The response body is decoded like the stdout of exec synthesizers.
+func NewServiceHandler(cli *http.Client) SynthesizerHandle {
+	return func(ctx context.Context, s *apiv1.Synthesizer, rl *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+		if s.Spec.Service == nil {
+			return nil, fmt.Errorf("synthesizer %q is not backed by a service", s.Name)
+		}
+
+		body, err := json.Marshal(rl)
 		if err != nil {
 			return nil, err
 		}
 
+		if s.Spec.ExecTimeout != nil {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.Spec.ExecTimeout.Duration)
+			defer cancel()
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Spec.Service.URL, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := cli.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			return nil, fmt.Errorf("synthesizer service returned status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+		}
+
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxStdoutBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("reading synthesizer service response: %w", err)
+		}
+
+		// Malformed responses are reported as a result since retrying won't fix them, just like exec output
+		if len(body) > maxStdoutBytes {
+			return outputErrorResult(fmt.Errorf("response body exceeded %d bytes", maxStdoutBytes)), nil
+		}
+		output, err := decodeOutput(bytes.NewReader(body))
+		if err != nil {
+			return outputErrorResult(err), nil
+		}
+
 		return output, nil
 	}
 }
@@ -2,11 +2,15 @@ package execution
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	apiv1 "github.com/Azure/eno/api/v1"
 	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -46,6 +50,39 @@ func TestExecHandlerTimeout(t *testing.T) {
 	require.EqualError(t, err, "signal: killed")
 }
 
+func TestExecHandlerFailure(t *testing.T) {
+	handle := NewExecHandler()
+
+	syn := &apiv1.Synthesizer{}
+	syn.Spec.Command = []string{"/bin/sh", "-c", "echo 'first line' >&2; echo 'something went wrong' >&2; exit 3"}
+	rl := &krmv1.ResourceList{}
+
+	_, err := handle(context.Background(), syn, rl)
+	require.EqualError(t, err, "exit status 3")
+
+	execErr := &ExecError{}
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, 3, execErr.ExitCode)
+	assert.Equal(t, "first line\nsomething went wrong", execErr.Stderr)
+	assert.Equal(t, "exit status 3: first line\nsomething went wrong", failureMessage(err))
+}
+
+func TestExecHandlerSignal(t *testing.T) {
+	handle := NewExecHandler()
+
+	syn := &apiv1.Synthesizer{}
+	syn.Spec.Command = []string{"/bin/sh", "-c", "kill -9 $$"}
+	rl := &krmv1.ResourceList{}
+
+	_, err := handle(context.Background(), syn, rl)
+	require.EqualError(t, err, "signal: killed")
+
+	execErr := &ExecError{}
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, -1, execErr.ExitCode)
+	assert.Equal(t, "signal: killed", failureMessage(err))
+}
+
 func TestExecHandlerEmpty(t *testing.T) {
 	handle := NewExecHandler()
 
@@ -55,3 +92,70 @@ func TestExecHandlerEmpty(t *testing.T) {
 	_, err := handle(context.Background(), syn, rl)
 	require.EqualError(t, err, "exec: \"synthesize\": executable file not found in $PATH")
 }
+
+func TestServiceHandler(t *testing.T) {
+	var received *krmv1.ResourceList
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/synthesize", r.URL.Path)
+		received = &krmv1.ResourceList{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(received))
+		json.NewEncoder(w).Encode(received)
+	}))
+	defer srv.Close()
+	handle := NewServiceHandler(srv.Client())
+
+	syn := &apiv1.Synthesizer{}
+	syn.Spec.Service = &apiv1.SynthesizerService{URL: srv.URL + "/synthesize"}
+	rl := &krmv1.ResourceList{Items: []*unstructured.Unstructured{{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]any{
+				"name":      "test",
+				"namespace": "default",
+			},
+		},
+	}}}
+
+	out, err := handle(context.Background(), syn, rl)
+	require.NoError(t, err)
+	require.Len(t, out.Items, 1)
+	require.Len(t, received.Items, 1)
+	assert.Equal(t, "test", out.Items[0].GetName())
+}
+
+func TestServiceHandlerErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fail":
+			http.Error(w, "boom", http.StatusServiceUnavailable)
+		case "/slow":
+			time.Sleep(time.Millisecond * 100)
+		default:
+			w.Write([]byte("not json"))
+		}
+	}))
+	defer srv.Close()
+	handle := NewServiceHandler(srv.Client())
+
+	syn := &apiv1.Synthesizer{}
+	syn.Name = "test-synth"
+	_, err := handle(context.Background(), syn, &krmv1.ResourceList{})
+	assert.EqualError(t, err, `synthesizer "test-synth" is not backed by a service`)
+
+	syn.Spec.Service = &apiv1.SynthesizerService{URL: srv.URL + "/fail"}
+	_, err = handle(context.Background(), syn, &krmv1.ResourceList{})
+	assert.EqualError(t, err, "synthesizer service returned status 503: boom")
+
+	syn.Spec.Service.URL = srv.URL + "/invalid"
+	out, err := handle(context.Background(), syn, &krmv1.ResourceList{})
+	require.NoError(t, err)
+	require.Len(t, out.Results, 1)
+	assert.Contains(t, out.Results[0].Message, "invalid synthesizer output")
+
+	syn.Spec.Service.URL = srv.URL + "/slow"
+	syn.Spec.ExecTimeout = &metav1.Duration{Duration: time.Millisecond}
+	_, err = handle(context.Background(), syn, &krmv1.ResourceList{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
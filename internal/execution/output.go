@@ -0,0 +1,126 @@
+package execution
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	// maxStdoutBytes is the max size of a synthesizer's output.
+	maxStdoutBytes = 1024 * 1024 * 64
+
+	// maxStderrTailBytes is the max amount of a failed synthesizer's stderr surfaced in the composition status.
+	maxStderrTailBytes = 1024
+)
+
+// decodeOutput parses the output of a synthesizer process. It can be either a ResourceList or a stream of objects,
+// encoded as JSON or (multi-document) YAML.
+func decodeOutput(r io.Reader) (*krmv1.ResourceList, error) {
+	docs := []json.RawMessage{}
+	dec := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		doc := json.RawMessage{}
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", len(docs), err)
+		}
+		if len(doc) == 0 || bytes.Equal(doc, []byte("null")) {
+			continue // empty yaml document
+		}
+		docs = append(docs, doc)
+	}
+	if len(docs) == 0 {
+		return nil, errors.New("no output was written to stdout")
+	}
+
+	// A single document without a kind is treated as a ResourceList for compatibility
+	if len(docs) == 1 {
+		meta := struct {
+			Kind string `json:"kind"`
+		}{}
+		if err := json.Unmarshal(docs[0], &meta); err == nil && (meta.Kind == krmv1.ResourceListKind || meta.Kind == "") {
+			rl := &krmv1.ResourceList{}
+			if err := json.Unmarshal(docs[0], rl); err != nil {
+				return nil, fmt.Errorf("decoding ResourceList: %w", err)
+			}
+			return rl, nil
+		}
+	}
+
+	rl := &krmv1.ResourceList{Kind: krmv1.ResourceListKind, APIVersion: krmv1.SchemeGroupVersion.String()}
+	for i, doc := range docs {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(doc); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		if obj.GetKind() == krmv1.ResourceListKind {
+			return nil, fmt.Errorf("document %d: a ResourceList must be the only document", i)
+		}
+		rl.Items = append(rl.Items, obj)
+	}
+	return rl, nil
+}
+
+// outputErrorResult returns a ResourceList that fails the synthesis with the given error.
+func outputErrorResult(err error) *krmv1.ResourceList {
+	return &krmv1.ResourceList{
+		Kind:       krmv1.ResourceListKind,
+		APIVersion: krmv1.SchemeGroupVersion.String(),
+		Results: []*krmv1.Result{{
+			Message:  fmt.Sprintf("invalid synthesizer output: %s", err),
+			Severity: krmv1.ResultSeverityError,
+		}},
+	}
+}
+
+// limitedBuffer buffers up to limit bytes and silently discards the rest.
+// Returning errors from Write would leave the process blocked on a full pipe.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (l *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := l.limit - l.Len(); len(p) > remaining {
+		l.truncated = true
+		l.Buffer.Write(p[:max(remaining, 0)])
+		return len(p), nil
+	}
+	return l.Buffer.Write(p)
+}
+
+// tailBuffer retains the last limit bytes written to it.
+type tailBuffer struct {
+	buf       []byte
+	limit     int
+	truncated bool
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - t.limit; over > 0 {
+		t.truncated = true
+		t.buf = append(t.buf[:0], t.buf[over:]...)
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	str := strings.ToValidUTF8(string(bytes.TrimSpace(t.buf)), "") // the tail may start mid-rune
+	if t.truncated && str != "" {
+		return "..." + str
+	}
+	return str
+}
@@ -0,0 +1,175 @@
+package execution
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeOutput(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Input   string
+		Names   []string
+		Results int
+		Error   string
+	}{
+		{
+			Name:  "json resource list",
+			Input: `{"apiVersion":"config.kubernetes.io/v1","kind":"ResourceList","items":[{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"a"}}],"results":[{"message":"hi","severity":"info"}]}`,
+			Names: []string{"a"}, Results: 1,
+		},
+		{
+			Name:  "json resource list without kind",
+			Input: `{"items":[{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"a"}}]}`,
+			Names: []string{"a"},
+		},
+		{
+			Name: "yaml resource list",
+			Input: `
+apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: a
+`,
+			Names: []string{"a"},
+		},
+		{
+			Name: "multi-document yaml",
+			Input: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+---
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+  annotations:
+    count: "1"
+`,
+			Names: []string{"a", "b"},
+		},
+		{
+			Name:  "single yaml object",
+			Input: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n",
+			Names: []string{"a"},
+		},
+		{
+			Name: "json stream",
+			Input: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"a"}}
+{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"b"}}`,
+			Names: []string{"a", "b"},
+		},
+		{
+			Name:  "empty",
+			Input: "\n",
+			Error: "no output was written to stdout",
+		},
+		{
+			Name:  "missing kind",
+			Input: "apiVersion: v1\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\n",
+			Error: "document 0: Object 'Kind' is missing in '{\"apiVersion\":\"v1\",\"metadata\":{\"name\":\"a\"}}'",
+		},
+		{
+			Name:  "nested resource list",
+			Input: "apiVersion: v1\nkind: ConfigMap\n---\napiVersion: config.kubernetes.io/v1\nkind: ResourceList\n",
+			Error: "document 1: a ResourceList must be the only document",
+		},
+		{
+			Name:  "invalid yaml",
+			Input: "apiVersion: v1\nkind: [\n",
+			Error: "document 0: error converting YAML to JSON: yaml: line 2: did not find expected node content",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			rl, err := decodeOutput(strings.NewReader(tc.Input))
+			if tc.Error != "" {
+				require.EqualError(t, err, tc.Error)
+				return
+			}
+			require.NoError(t, err)
+
+			names := []string{}
+			for _, item := range rl.Items {
+				names = append(names, item.GetName())
+			}
+			assert.Equal(t, tc.Names, names)
+			assert.Len(t, rl.Results, tc.Results)
+		})
+	}
+}
+
+func TestExecHandlerYAMLOutput(t *testing.T) {
+	handle := NewExecHandler()
+
+	syn := &apiv1.Synthesizer{}
+	syn.Spec.Command = []string{"/bin/sh", "-c", "printf 'apiVersion: v1\\nkind: ConfigMap\\nmetadata:\\n  name: a\\n---\\napiVersion: v1\\nkind: Secret\\nmetadata:\\n  name: b\\n'"}
+
+	out, err := handle(context.Background(), syn, &krmv1.ResourceList{})
+	require.NoError(t, err)
+	require.Len(t, out.Items, 2)
+	assert.Equal(t, "ConfigMap", out.Items[0].GetKind())
+	assert.Equal(t, "Secret", out.Items[1].GetKind())
+	assert.Empty(t, out.Results)
+}
+
+func TestExecHandlerInvalidOutput(t *testing.T) {
+	handle := NewExecHandler()
+
+	syn := &apiv1.Synthesizer{}
+	syn.Spec.Command = []string{"/bin/sh", "-c", "echo 'not: [valid'"}
+
+	out, err := handle(context.Background(), syn, &krmv1.ResourceList{})
+	require.NoError(t, err)
+	assert.Empty(t, out.Items)
+	require.Len(t, out.Results, 1)
+	assert.Equal(t, krmv1.ResultSeverityError, out.Results[0].Severity)
+	assert.Contains(t, out.Results[0].Message, "invalid synthesizer output: document 0: ")
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	buf := &limitedBuffer{limit: 5}
+
+	n, err := buf.Write([]byte("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.False(t, buf.truncated)
+
+	n, err = buf.Write([]byte("defg"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.True(t, buf.truncated)
+	assert.Equal(t, "abcde", buf.String())
+
+	n, err = buf.Write([]byte("h"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "abcde", buf.String())
+}
+
+func TestTailBuffer(t *testing.T) {
+	buf := &tailBuffer{limit: 5}
+
+	buf.Write([]byte(" abc"))
+	assert.Equal(t, "abc", buf.String())
+
+	buf.Write([]byte("defg\n"))
+	assert.True(t, buf.truncated)
+	assert.Equal(t, "...defg", buf.String())
+
+	buf.Write([]byte("hijklmnop"))
+	assert.Equal(t, "...lmnop", buf.String())
+}
@@ -0,0 +1,150 @@
+package execution
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StepTag is added to the results of synthesizers that declare functions to identify the step that produced them.
+const StepTag = "eno.azure.io/step"
+
+// synthesizerStepName is the name of the first step of every pipeline i.e. the synthesizer's own command.
+const synthesizerStepName = "synthesizer"
+
+type pipelineStep struct {
+	name        string
+	synthesizer *apiv1.Synthesizer // with the step's image and command
+}
+
+// pipelineSteps returns the steps executed by the synthesizer: its own command followed by any functions.
+func pipelineSteps(syn *apiv1.Synthesizer) []pipelineStep {
+	steps := []pipelineStep{{name: synthesizerStepName, synthesizer: syn}}
+	for _, fn := range syn.Spec.Functions {
+		s := syn.DeepCopy()
+		s.Spec.Image = fn.Image
+		s.Spec.Command = fn.Command
+		steps = append(steps, pipelineStep{name: fn.Name, synthesizer: s})
+	}
+	return steps
+}
+
+func (p *pipelineStep) wrapError(err error) error {
+	if p.name == synthesizerStepName {
+		return fmt.Errorf("executing synthesizer: %w", err)
+	}
+	return fmt.Errorf("executing function %q: %w", p.name, err)
+}
+
+// pipelineState is written to the pipeline dir by each step of a pipeline, and read by the next one.
+type pipelineState struct {
+	Output         *krmv1.ResourceList    `json:"output"`
+	Results        []*krmv1.Result        `json:"results,omitempty"`
+	Steps          []apiv1.SynthesisStep  `json:"steps,omitempty"`
+	InputHash      string                 `json:"inputHash"`
+	InputRevisions []apiv1.InputRevisions `json:"inputRevisions,omitempty"`
+	EnvRevisions   []apiv1.InputRevisions `json:"envRevisions,omitempty"`
+}
+
+func (e *Executor) pipelineStatePath(step int) (string, error) {
+	if e.PipelineDir == "" {
+		return "", errors.New("pipeline dir is not configured")
+	}
+	return filepath.Join(e.PipelineDir, fmt.Sprintf("step-%d.json", step)), nil
+}
+
+// writePipelineState hands the output of the given step to the next one.
+func (e *Executor) writePipelineState(step int, inputs *synthesisInputs, hash string) error {
+	path, err := e.pipelineStatePath(step)
+	if err != nil {
+		return err
+	}
+
+	js, err := json.Marshal(&pipelineState{
+		Output:         inputs.resources,
+		Results:        inputs.results,
+		Steps:          inputs.steps,
+		InputHash:      hash,
+		InputRevisions: inputs.revisions,
+		EnvRevisions:   inputs.envRevisions,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(e.PipelineDir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	// Write atomically since the step's container could be killed at any point
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, js, 0o600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readPipelineState returns the inputs of the step following the given one, along with the synthesis's input hash.
+func (e *Executor) readPipelineState(step int) (*synthesisInputs, string, error) {
+	path, err := e.pipelineStatePath(step)
+	if err != nil {
+		return nil, "", err
+	}
+
+	js, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	state := &pipelineState{}
+	err = json.Unmarshal(js, state)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &synthesisInputs{
+		resources:    state.Output,
+		revisions:    state.InputRevisions,
+		envRevisions: state.EnvRevisions,
+		steps:        state.Steps,
+		results:      state.Results,
+	}, state.InputHash, nil
+}
+
+// next returns the inputs of the step following the one that produced the given output.
+// The output's results are tagged with the name of the step and set aside until the pipeline completes.
+func (s *synthesisInputs) next(step string, output *krmv1.ResourceList, latency time.Duration) *synthesisInputs {
+	next := *s
+	next.steps = append(slices.Clone(s.steps), apiv1.SynthesisStep{Name: step, Duration: metav1.Duration{Duration: latency}})
+	next.results = slices.Clone(s.results)
+	for _, result := range output.Results {
+		result := *result
+		result.Tags = maps.Clone(result.Tags)
+		if result.Tags == nil {
+			result.Tags = map[string]string{}
+		}
+		result.Tags[StepTag] = step
+		next.results = append(next.results, &result)
+	}
+
+	rl := *output
+	rl.Results = nil
+	rl.FunctionConfig = s.resources.FunctionConfig // every step is given the composition's values
+	next.resources = &rl
+	return &next
+}
+
+func hasErrorResult(rl *krmv1.ResourceList) bool {
+	return slices.ContainsFunc(rl.Results, func(r *krmv1.Result) bool { return r.Severity == krmv1.ResultSeverityError })
+}
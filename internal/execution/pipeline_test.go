@@ -0,0 +1,155 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func setupPipelineTest(t *testing.T, handler SynthesizerHandle) (client.Client, *Executor, *apiv1.Composition, *Env) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiv1.SchemeBuilder.AddToScheme(scheme))
+
+	cli := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&apiv1.ResourceSlice{}, &apiv1.Composition{}).
+		Build()
+
+	syn := &apiv1.Synthesizer{}
+	syn.Name = "test-synth"
+	syn.Spec.Image = "synth"
+	syn.Spec.Functions = []apiv1.SynthesizerFunction{
+		{Name: "first", Image: "first-image"},
+		{Name: "second", Image: "second-image"},
+	}
+	require.NoError(t, cli.Create(ctx, syn))
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Spec.Synthesizer.Name = syn.Name
+	require.NoError(t, cli.Create(ctx, comp))
+
+	comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "test-uuid"}
+	require.NoError(t, cli.Status().Update(ctx, comp))
+
+	e := &Executor{
+		Reader:      cli,
+		Writer:      cli,
+		Handler:     handler,
+		PipelineDir: t.TempDir(),
+	}
+	env := &Env{
+		CompositionName:      comp.Name,
+		CompositionNamespace: comp.Namespace,
+		SynthesisUUID:        comp.Status.CurrentSynthesis.UUID,
+	}
+	return cli, e, comp, env
+}
+
+func TestPipeline(t *testing.T) {
+	ctx := context.Background()
+	cli, e, comp, env := setupPipelineTest(t, func(ctx context.Context, s *apiv1.Synthesizer, rl *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+		// Each step appends a configmap named after its image
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName(s.Spec.Image)
+		obj.SetNamespace("default")
+
+		assert.Empty(t, rl.Results, "results aren't passed between steps")
+		return &krmv1.ResourceList{
+			Items:   append(rl.Items, obj),
+			Results: []*krmv1.Result{{Message: "ran " + s.Spec.Image, Severity: krmv1.ResultSeverityInfo}},
+		}, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		env.PipelineStep = i
+		require.NoError(t, e.Synthesize(ctx, env))
+
+		require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+		assert.Equal(t, i == 2, comp.Status.CurrentSynthesis.Synthesized != nil, "only the last step completes the synthesis")
+	}
+
+	synthesis := comp.Status.CurrentSynthesis
+	require.Len(t, synthesis.Steps, 3)
+	assert.Equal(t, "synthesizer", synthesis.Steps[0].Name)
+	assert.Equal(t, "first", synthesis.Steps[1].Name)
+	assert.Equal(t, "second", synthesis.Steps[2].Name)
+	assert.NotEmpty(t, synthesis.InputHash)
+
+	assert.Equal(t, []apiv1.Result{
+		{Message: "ran synth", Severity: "info", Tags: map[string]string{StepTag: "synthesizer"}},
+		{Message: "ran first-image", Severity: "info", Tags: map[string]string{StepTag: "first"}},
+		{Message: "ran second-image", Severity: "info", Tags: map[string]string{StepTag: "second"}},
+	}, synthesis.Results)
+
+	require.Len(t, synthesis.ResourceSlices, 1)
+	slice := &apiv1.ResourceSlice{}
+	slice.Name = synthesis.ResourceSlices[0].Name
+	slice.Namespace = comp.Namespace
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(slice), slice))
+	assert.Len(t, slice.Spec.Resources, 3)
+}
+
+func TestPipelineErrorResult(t *testing.T) {
+	ctx := context.Background()
+	var calls int
+	cli, e, comp, env := setupPipelineTest(t, func(ctx context.Context, s *apiv1.Synthesizer, rl *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+		calls++
+		if s.Spec.Image == "first-image" {
+			return &krmv1.ResourceList{Results: []*krmv1.Result{{Message: "denied", Severity: krmv1.ResultSeverityError}}}, nil
+		}
+		return rl, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		env.PipelineStep = i
+		require.NoError(t, e.Synthesize(ctx, env))
+	}
+	assert.Equal(t, 2, calls, "the pipeline stops at the failed step")
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	synthesis := comp.Status.CurrentSynthesis
+	assert.NotNil(t, synthesis.Synthesized)
+	assert.True(t, synthesis.Failed())
+	assert.Len(t, synthesis.Steps, 2)
+	assert.Equal(t, []apiv1.Result{{Message: "denied", Severity: "error", Tags: map[string]string{StepTag: "first"}}}, synthesis.Results)
+}
+
+func TestPipelineStepFailure(t *testing.T) {
+	ctx := context.Background()
+	cli, e, comp, env := setupPipelineTest(t, func(ctx context.Context, s *apiv1.Synthesizer, rl *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+		if s.Spec.Image == "second-image" {
+			return nil, &ExecError{ExitCode: 1, err: assert.AnError}
+		}
+		return rl, nil
+	})
+
+	env.PipelineStep = 2
+	err := e.Synthesize(ctx, env)
+	require.Error(t, err, "the output of the previous step doesn't exist")
+
+	for i := 0; i < 3; i++ {
+		env.PipelineStep = i
+		err = e.Synthesize(ctx, env)
+	}
+	require.Error(t, err)
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	synthesis := comp.Status.CurrentSynthesis
+	assert.Nil(t, synthesis.Synthesized)
+	assert.Equal(t, 1, synthesis.Failures)
+	require.Len(t, synthesis.Results, 1)
+	assert.Contains(t, synthesis.Results[0].Message, `executing function "second"`)
+}
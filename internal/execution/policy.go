@@ -0,0 +1,144 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	"github.com/Azure/eno/internal/readiness"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	"github.com/go-logr/logr"
+	"github.com/google/cel-go/cel"
+	celtypes "github.com/google/cel-go/common/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// PolicyTag is added to the results produced by policy violations to identify the policy.
+const PolicyTag = "eno.azure.io/policy"
+
+// maxPolicyResults limits the number of policy violations reported in the composition status.
+const maxPolicyResults = 10
+
+type policyRule struct {
+	policy  string
+	rule    *apiv1.PolicyRule
+	program cel.Program
+}
+
+func (p *policyRule) severity() string {
+	if p.rule.Action == apiv1.PolicyActionWarn {
+		return krmv1.ResultSeverityWarning
+	}
+	return krmv1.ResultSeverityError
+}
+
+// eval returns a description of the violation when the rule isn't satisfied by the given resource.
+func (p *policyRule) eval(ctx context.Context, obj *unstructured.Unstructured) (string, bool) {
+	msg := p.rule.Message
+	if msg == "" {
+		msg = fmt.Sprintf("expected %s", p.rule.Expression)
+	}
+
+	val, _, err := p.program.ContextEval(ctx, map[string]any{"self": obj.Object})
+	if err != nil {
+		return fmt.Sprintf("%s (%s)", msg, err), false
+	}
+	return msg, val == celtypes.True
+}
+
+// evaluatePolicies returns a result for every policy rule violated by a synthesized resource.
+// Deny rules produce error results, and warn rules produce warnings.
+func (e *Executor) evaluatePolicies(ctx context.Context, rl *krmv1.ResourceList) ([]*krmv1.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+
+	list := &apiv1.PolicyList{}
+	err := e.Reader.List(ctx, list)
+	if err != nil {
+		return nil, fmt.Errorf("listing policies: %w", err)
+	}
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+
+	renv, err := readiness.NewEnv()
+	if err != nil {
+		return nil, fmt.Errorf("building cel env: %w", err)
+	}
+
+	// Invalid rules fail the synthesis rather than being ignored, since policies are guardrails
+	var results []*krmv1.Result
+	var rules []*policyRule
+	for _, policy := range list.Items {
+		for i := range policy.Spec.Rules {
+			rule := &policy.Spec.Rules[i]
+			prgm, err := renv.Compile(rule.Expression)
+			if err != nil {
+				results = append(results, &krmv1.Result{
+					Message:  fmt.Sprintf("policy %q rule %q is invalid: %s", policy.Name, rule.Name, err),
+					Severity: krmv1.ResultSeverityError,
+					Tags:     map[string]string{PolicyTag: policy.Name},
+				})
+				continue
+			}
+			rules = append(rules, &policyRule{policy: policy.Name, rule: rule, program: prgm})
+		}
+	}
+
+	var denied, warned, omittedDenied, omittedWarned int
+	for _, obj := range rl.Items {
+		if obj.GroupVersionKind() == patchGVK {
+			continue
+		}
+		for _, rule := range rules {
+			msg, ok := rule.eval(ctx, obj)
+			if ok {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err // evaluation was interrupted
+			}
+
+			severity := rule.severity()
+			if severity == krmv1.ResultSeverityError {
+				denied++
+			} else {
+				warned++
+			}
+			if denied+warned > maxPolicyResults {
+				if severity == krmv1.ResultSeverityError {
+					omittedDenied++
+				} else {
+					omittedWarned++
+				}
+				continue
+			}
+
+			results = append(results, &krmv1.Result{
+				Message:  fmt.Sprintf("resource %s violates policy %q rule %q: %s", describeResource(obj), rule.policy, rule.rule.Name, msg),
+				Severity: severity,
+				Tags:     map[string]string{PolicyTag: rule.policy},
+				ResourceRef: &krmv1.ResultResourceRef{
+					APIVersion: obj.GetAPIVersion(),
+					Kind:       obj.GetKind(),
+					Name:       obj.GetName(),
+					Namespace:  obj.GetNamespace(),
+				},
+			})
+		}
+	}
+	if omittedDenied > 0 {
+		results = append(results, &krmv1.Result{
+			Message:  fmt.Sprintf("%d more violations of deny rules", omittedDenied),
+			Severity: krmv1.ResultSeverityError,
+		})
+	}
+	if omittedWarned > 0 {
+		results = append(results, &krmv1.Result{
+			Message:  fmt.Sprintf("%d more violations of warn rules", omittedWarned),
+			Severity: krmv1.ResultSeverityWarning,
+		})
+	}
+
+	logger.V(0).Info("evaluated policies", "policies", len(list.Items), "denied", denied, "warned", warned)
+	return results, nil
+}
@@ -0,0 +1,168 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newPolicyTestClient(t *testing.T, policies ...*apiv1.Policy) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiv1.SchemeBuilder.AddToScheme(scheme))
+
+	builder := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&apiv1.ResourceSlice{}, &apiv1.Composition{})
+	for _, policy := range policies {
+		builder.WithObjects(policy)
+	}
+	return builder.Build()
+}
+
+func newTestPolicy(name string, rules ...apiv1.PolicyRule) *apiv1.Policy {
+	policy := &apiv1.Policy{}
+	policy.Name = name
+	policy.Spec.Rules = rules
+	return policy
+}
+
+func TestEvaluatePolicies(t *testing.T) {
+	ctx := context.Background()
+	cli := newPolicyTestClient(t,
+		newTestPolicy("rbac", apiv1.PolicyRule{
+			Name:       "no-cluster-roles",
+			Expression: "self.kind != 'ClusterRole'",
+			Message:    "cluster roles aren't allowed",
+			Action:     apiv1.PolicyActionDeny,
+		}),
+		newTestPolicy("labels", apiv1.PolicyRule{
+			Name:       "team",
+			Expression: "self.metadata.labels.team != ''",
+			Action:     apiv1.PolicyActionWarn,
+		}))
+	e := &Executor{Reader: cli}
+
+	compliant := newTestObject("ConfigMap", "compliant")
+	compliant.SetLabels(map[string]string{"team": "foo"})
+	denied := newTestObject("ClusterRole", "denied")
+	denied.SetLabels(map[string]string{"team": "foo"})
+
+	rl := &krmv1.ResourceList{Items: []*unstructured.Unstructured{
+		compliant,
+		newTestObject("ConfigMap", "unlabeled"),
+		denied,
+		{Object: map[string]any{"apiVersion": "eno.azure.io/v1", "kind": "Patch", "metadata": map[string]any{"name": "patch"}}},
+	}}
+
+	results, err := e.evaluatePolicies(ctx, rl)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Contains(t, results[0].Message, `resource ConfigMap "unlabeled" in namespace "default" violates policy "labels" rule "team": expected self.metadata.labels.team != '' (`)
+	assert.Equal(t, krmv1.ResultSeverityWarning, results[0].Severity)
+	assert.Equal(t, map[string]string{PolicyTag: "labels"}, results[0].Tags)
+	assert.Equal(t, "unlabeled", results[0].ResourceRef.Name)
+
+	assert.Equal(t, `resource ClusterRole "denied" in namespace "default" violates policy "rbac" rule "no-cluster-roles": cluster roles aren't allowed`, results[1].Message)
+	assert.Equal(t, krmv1.ResultSeverityError, results[1].Severity)
+	assert.Equal(t, map[string]string{PolicyTag: "rbac"}, results[1].Tags)
+}
+
+func TestEvaluatePoliciesInvalidRule(t *testing.T) {
+	ctx := context.Background()
+	cli := newPolicyTestClient(t, newTestPolicy("invalid", apiv1.PolicyRule{Name: "syntax", Expression: "self.kind =="}))
+	e := &Executor{Reader: cli}
+
+	rl := &krmv1.ResourceList{Items: []*unstructured.Unstructured{newTestObject("ConfigMap", "test")}}
+	results, err := e.evaluatePolicies(ctx, rl)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Contains(t, results[0].Message, `policy "invalid" rule "syntax" is invalid: `)
+	assert.Equal(t, krmv1.ResultSeverityError, results[0].Severity)
+}
+
+func TestEvaluatePoliciesLimit(t *testing.T) {
+	ctx := context.Background()
+	cli := newPolicyTestClient(t,
+		newTestPolicy("deny", apiv1.PolicyRule{Name: "deny-all", Expression: "false"}),
+		newTestPolicy("warn", apiv1.PolicyRule{Name: "warn-all", Expression: "false", Action: apiv1.PolicyActionWarn}))
+	e := &Executor{Reader: cli}
+
+	rl := &krmv1.ResourceList{}
+	for i := 0; i < maxPolicyResults; i++ {
+		rl.Items = append(rl.Items, newTestObject("ConfigMap", fmt.Sprintf("test-%d", i)))
+	}
+
+	results, err := e.evaluatePolicies(ctx, rl)
+	require.NoError(t, err)
+	require.Len(t, results, maxPolicyResults+2)
+	assert.Equal(t, "5 more violations of deny rules", results[maxPolicyResults].Message)
+	assert.Equal(t, krmv1.ResultSeverityError, results[maxPolicyResults].Severity)
+	assert.Equal(t, "5 more violations of warn rules", results[maxPolicyResults+1].Message)
+	assert.Equal(t, krmv1.ResultSeverityWarning, results[maxPolicyResults+1].Severity)
+}
+
+func TestSynthesizePolicies(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Action       apiv1.PolicyAction
+		ExpectFailed bool
+	}{
+		{Name: "deny", Action: apiv1.PolicyActionDeny, ExpectFailed: true},
+		{Name: "warn", Action: apiv1.PolicyActionWarn, ExpectFailed: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			cli := newPolicyTestClient(t, newTestPolicy("test", apiv1.PolicyRule{
+				Name:       "no-configmaps",
+				Expression: "self.kind != 'ConfigMap'",
+				Action:     tc.Action,
+			}))
+
+			syn := &apiv1.Synthesizer{}
+			syn.Name = "test-synth"
+			require.NoError(t, cli.Create(ctx, syn))
+
+			comp := &apiv1.Composition{}
+			comp.Name = "test-comp"
+			comp.Namespace = "default"
+			comp.Spec.Synthesizer.Name = syn.Name
+			require.NoError(t, cli.Create(ctx, comp))
+
+			comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "test-uuid"}
+			require.NoError(t, cli.Status().Update(ctx, comp))
+
+			e := &Executor{
+				Reader: cli,
+				Writer: cli,
+				Handler: func(ctx context.Context, s *apiv1.Synthesizer, rl *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+					return &krmv1.ResourceList{Items: []*unstructured.Unstructured{newTestObject("ConfigMap", "test")}}, nil
+				},
+			}
+			env := &Env{
+				CompositionName:      comp.Name,
+				CompositionNamespace: comp.Namespace,
+				SynthesisUUID:        comp.Status.CurrentSynthesis.UUID,
+			}
+			require.NoError(t, e.Synthesize(ctx, env))
+
+			require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+			synthesis := comp.Status.CurrentSynthesis
+			assert.NotNil(t, synthesis.Synthesized)
+			assert.Equal(t, tc.ExpectFailed, synthesis.Failed())
+			assert.Equal(t, tc.ExpectFailed, len(synthesis.ResourceSlices) == 0, "slices are only written when no deny rules are violated")
+			require.Len(t, synthesis.Results, 1)
+			assert.Equal(t, "test", synthesis.Results[0].Tags[PolicyTag])
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// maxValidationResults limits the number of invalid resources reported in the composition status.
+const maxValidationResults = 10
+
+// patchGVK identifies the pseudo-resources used to patch existing resources, which can't be validated.
+var patchGVK = schema.GroupVersionKind{Group: "eno.azure.io", Version: "v1", Kind: "Patch"}
+
+// validateOutput returns an error result for every synthesized resource that isn't valid according to the synthesizer's validation mode.
+// Errors are only returned when the resources couldn't be validated e.g. because the apiserver is unavailable.
+func (e *Executor) validateOutput(ctx context.Context, syn *apiv1.Synthesizer, rl *krmv1.ResourceList) ([]*krmv1.Result, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+
+	var validate func(context.Context, *unstructured.Unstructured) (string, error)
+	switch syn.Spec.Validation {
+	case apiv1.ValidationModeSchema:
+		validate = e.validateSchema
+	case apiv1.ValidationModeDryRun:
+		validate = e.validateDryRun
+	default:
+		return nil, fmt.Errorf("unknown validation mode %q", syn.Spec.Validation)
+	}
+
+	var results []*krmv1.Result
+	var invalid int
+	for _, obj := range rl.Items {
+		if obj.GroupVersionKind() == patchGVK {
+			continue
+		}
+
+		problem := "missing name, kind, or apiVersion"
+		if obj.GetName() != "" && obj.GetKind() != "" && obj.GetAPIVersion() != "" {
+			var err error
+			problem, err = validate(ctx, obj)
+			if err != nil {
+				return nil, fmt.Errorf("validating %s: %w", describeResource(obj), err)
+			}
+		}
+		if problem == "" {
+			continue
+		}
+
+		invalid++
+		if invalid <= maxValidationResults {
+			results = append(results, &krmv1.Result{
+				Message:  fmt.Sprintf("invalid resource %s: %s", describeResource(obj), problem),
+				Severity: krmv1.ResultSeverityError,
+				ResourceRef: &krmv1.ResultResourceRef{
+					APIVersion: obj.GetAPIVersion(),
+					Kind:       obj.GetKind(),
+					Name:       obj.GetName(),
+					Namespace:  obj.GetNamespace(),
+				},
+			})
+		}
+	}
+	if invalid > maxValidationResults {
+		results = append(results, &krmv1.Result{
+			Message:  fmt.Sprintf("%d more resources are invalid", invalid-maxValidationResults),
+			Severity: krmv1.ResultSeverityError,
+		})
+	}
+
+	logger.V(0).Info("validated synthesized resources", "mode", syn.Spec.Validation, "invalid", invalid)
+	return results, nil
+}
+
+// validateSchema checks the resource against the apiserver's openapi schema.
+// Resources of types that aren't in the schema are considered to be valid.
+func (e *Executor) validateSchema(ctx context.Context, obj *unstructured.Unstructured) (string, error) {
+	if e.Schemas == nil {
+		return "", errors.New("schema validation is not supported by this executor")
+	}
+
+	typeref, schem, err := e.Schemas.Get(ctx, obj.GroupVersionKind())
+	if err != nil {
+		return "", err
+	}
+	if typeref == nil {
+		return "", nil // unknown type
+	}
+
+	_, err = typed.AsTyped(value.NewValueInterface(obj.Object), schem, *typeref)
+	if err != nil {
+		return err.Error(), nil
+	}
+	return "", nil
+}
+
+// validateDryRun applies the resource using a server-side dry-run request.
+// Resources that depend on namespaces or CRDs that don't exist yet are considered to be valid, since they may be created by the same synthesis.
+//
+// Requests are sent with the synthesizer's identity, which must be allowed to patch the resources.
+// Forbidden responses are reported as invalid resources, since admission webhooks also use them to deny requests.
+func (e *Executor) validateDryRun(ctx context.Context, obj *unstructured.Unstructured) (string, error) {
+	err := e.Writer.Patch(ctx, obj.DeepCopy(), client.Apply, client.DryRunAll, client.ForceOwnership, client.FieldOwner("eno"))
+	switch {
+	case err == nil:
+		return "", nil
+	case meta.IsNoMatchError(err) || apierrors.IsNotFound(err):
+		return "", nil
+	case apierrors.IsInvalid(err) || apierrors.IsBadRequest(err):
+		return err.Error(), nil
+	case apierrors.IsForbidden(err):
+		return fmt.Sprintf("dry-run request was denied (the synthesizer's service account must be allowed to patch the resource): %s", err), nil
+	default:
+		return "", err
+	}
+}
+
+func describeResource(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %q", obj.GetKind(), obj.GetName())
+	}
+	return fmt.Sprintf("%s %q in namespace %q", obj.GetKind(), obj.GetName(), obj.GetNamespace())
+}
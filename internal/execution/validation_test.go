@@ -0,0 +1,224 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	apiv1 "github.com/Azure/eno/api/v1"
+	krmv1 "github.com/Azure/eno/pkg/krm/functions/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	smdschema "sigs.k8s.io/structured-merge-diff/v4/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+const testConfigMapSchema = `types:
+- name: configmap
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: untyped
+    - name: data
+      type:
+        map:
+          elementType:
+            scalar: string
+- name: untyped
+  scalar: untyped
+  list:
+    elementType:
+      namedType: untyped
+    elementRelationship: atomic
+  map:
+    elementType:
+      namedType: untyped
+    elementRelationship: separable
+`
+
+type testSchemaGetter struct {
+	schema *smdschema.Schema
+}
+
+func (t *testSchemaGetter) Get(ctx context.Context, gvk schema.GroupVersionKind) (*smdschema.TypeRef, *smdschema.Schema, error) {
+	if gvk.Kind != "ConfigMap" {
+		return nil, nil, nil
+	}
+	name := "configmap"
+	return &smdschema.TypeRef{NamedType: &name}, t.schema, nil
+}
+
+func TestValidateOutputSchema(t *testing.T) {
+	ctx := context.Background()
+	parser, err := typed.NewParser(testConfigMapSchema)
+	require.NoError(t, err)
+	e := &Executor{Schemas: &testSchemaGetter{schema: &parser.Schema}}
+
+	syn := &apiv1.Synthesizer{}
+	syn.Spec.Validation = apiv1.ValidationModeSchema
+
+	invalid := newTestObject("ConfigMap", "invalid")
+	unstructured.SetNestedField(invalid.Object, int64(123), "data", "foo")
+
+	rl := &krmv1.ResourceList{Items: []*unstructured.Unstructured{
+		newTestObject("ConfigMap", "valid"),
+		invalid,
+		newTestObject("UnknownKind", "unknown"),
+		{Object: map[string]any{"apiVersion": "v1", "kind": "ConfigMap"}},
+	}}
+
+	results, err := e.validateOutput(ctx, syn, rl)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Contains(t, results[0].Message, `invalid resource ConfigMap "invalid" in namespace "default": `)
+	assert.Equal(t, "invalid", results[0].ResourceRef.Name)
+	assert.Equal(t, krmv1.ResultSeverityError, results[0].Severity)
+	assert.Equal(t, `invalid resource ConfigMap "": missing name, kind, or apiVersion`, results[1].Message)
+}
+
+func TestValidateOutputDryRun(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiv1.SchemeBuilder.AddToScheme(scheme))
+
+	var calls int
+	cli := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				calls++
+				po := &client.PatchOptions{}
+				po.ApplyOptions(opts)
+				assert.Equal(t, []string{"All"}, po.DryRun)
+				assert.Equal(t, "eno", po.FieldManager)
+
+				gk := schema.GroupKind{Kind: "ConfigMap"}
+				switch obj.GetName() {
+				case "invalid":
+					return errors.NewInvalid(gk, obj.GetName(), field.ErrorList{field.Invalid(field.NewPath("data"), nil, "bad data")})
+				case "missing-namespace":
+					return errors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "default")
+				case "forbidden":
+					return errors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, obj.GetName(), fmt.Errorf("no access"))
+				case "unavailable":
+					return errors.NewServiceUnavailable("try again later")
+				}
+				return nil
+			},
+		}).
+		Build()
+	e := &Executor{Writer: cli}
+
+	syn := &apiv1.Synthesizer{}
+	syn.Spec.Validation = apiv1.ValidationModeDryRun
+
+	rl := &krmv1.ResourceList{Items: []*unstructured.Unstructured{
+		newTestObject("ConfigMap", "valid"),
+		newTestObject("ConfigMap", "invalid"),
+		newTestObject("ConfigMap", "missing-namespace"),
+		newTestObject("ConfigMap", "forbidden"),
+		{Object: map[string]any{"apiVersion": "eno.azure.io/v1", "kind": "Patch", "metadata": map[string]any{"name": "patch"}}},
+	}}
+
+	results, err := e.validateOutput(ctx, syn, rl)
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls, "patches aren't validated")
+	require.Len(t, results, 2)
+	assert.Contains(t, results[0].Message, `invalid resource ConfigMap "invalid" in namespace "default": `)
+	assert.Contains(t, results[0].Message, "bad data")
+	assert.Contains(t, results[1].Message, `invalid resource ConfigMap "forbidden" in namespace "default": dry-run request was denied`)
+
+	// Errors that aren't caused by the resource are returned
+	rl.Items = append(rl.Items, newTestObject("ConfigMap", "unavailable"))
+	_, err = e.validateOutput(ctx, syn, rl)
+	require.Error(t, err)
+}
+
+func TestValidateOutputLimit(t *testing.T) {
+	ctx := context.Background()
+	e := &Executor{}
+	syn := &apiv1.Synthesizer{}
+	syn.Spec.Validation = apiv1.ValidationModeSchema
+
+	rl := &krmv1.ResourceList{}
+	for i := 0; i < maxValidationResults+5; i++ {
+		rl.Items = append(rl.Items, &unstructured.Unstructured{Object: map[string]any{"kind": fmt.Sprintf("Kind%d", i)}})
+	}
+
+	results, err := e.validateOutput(ctx, syn, rl)
+	require.NoError(t, err)
+	require.Len(t, results, maxValidationResults+1)
+	assert.Equal(t, "5 more resources are invalid", results[maxValidationResults].Message)
+}
+
+func TestSynthesizeInvalidOutput(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiv1.SchemeBuilder.AddToScheme(scheme))
+
+	cli := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&apiv1.ResourceSlice{}, &apiv1.Composition{}).
+		Build()
+
+	syn := &apiv1.Synthesizer{}
+	syn.Name = "test-synth"
+	syn.Spec.Validation = apiv1.ValidationModeSchema
+	require.NoError(t, cli.Create(ctx, syn))
+
+	comp := &apiv1.Composition{}
+	comp.Name = "test-comp"
+	comp.Namespace = "default"
+	comp.Spec.Synthesizer.Name = syn.Name
+	require.NoError(t, cli.Create(ctx, comp))
+
+	comp.Status.CurrentSynthesis = &apiv1.Synthesis{UUID: "test-uuid"}
+	require.NoError(t, cli.Status().Update(ctx, comp))
+
+	parser, err := typed.NewParser(testConfigMapSchema)
+	require.NoError(t, err)
+
+	e := &Executor{
+		Reader:  cli,
+		Writer:  cli,
+		Schemas: &testSchemaGetter{schema: &parser.Schema},
+		Handler: func(ctx context.Context, s *apiv1.Synthesizer, rl *krmv1.ResourceList) (*krmv1.ResourceList, error) {
+			invalid := newTestObject("ConfigMap", "invalid")
+			unstructured.SetNestedField(invalid.Object, int64(123), "data", "foo")
+			return &krmv1.ResourceList{Items: []*unstructured.Unstructured{newTestObject("ConfigMap", "valid"), invalid}}, nil
+		},
+	}
+	env := &Env{
+		CompositionName:      comp.Name,
+		CompositionNamespace: comp.Namespace,
+		SynthesisUUID:        comp.Status.CurrentSynthesis.UUID,
+	}
+	require.NoError(t, e.Synthesize(ctx, env))
+
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(comp), comp))
+	synthesis := comp.Status.CurrentSynthesis
+	assert.NotNil(t, synthesis.Synthesized)
+	assert.True(t, synthesis.Failed())
+	assert.Empty(t, synthesis.ResourceSlices, "slices aren't written")
+	require.Len(t, synthesis.Results, 1)
+	assert.Contains(t, synthesis.Results[0].Message, `ConfigMap "invalid"`)
+
+	slices := &apiv1.ResourceSliceList{}
+	require.NoError(t, cli.List(ctx, slices))
+	assert.Empty(t, slices.Items)
+}
@@ -20,6 +20,7 @@ const (
 	IdxResourceSlicesByComposition = ".resourceSlicesByComposition"
 	IdxCompositionsByBinding       = ".compositionsByBinding"
 	IdxSynthesizersByRef           = ".synthesizersByRef"
+	IdxCompositionsByEnvRef        = ".compositionsByEnvRef"
 
 	CompositionNameLabelKey      = "eno.azure.io/composition-name"
 	CompositionNamespaceLabelKey = "eno.azure.io/composition-namespace"
@@ -102,3 +103,27 @@ func indexSynthRefs() client.IndexerFunc {
 		return keys
 	}
 }
+
+// indexEnvRefs indexes compositions by the Secrets and ConfigMaps referenced by their synthesisEnv that should trigger resynthesis.
+// Values are kind/name - the objects are always in the composition's namespace.
+func indexEnvRefs() client.IndexerFunc {
+	return func(o client.Object) []string {
+		comp, ok := o.(*apiv1.Composition)
+		if !ok {
+			return nil
+		}
+
+		keys := []string{}
+		for _, ev := range comp.Spec.SynthesisEnv {
+			src := ev.ValueFrom
+			switch {
+			case src == nil || !src.Resynthesize:
+			case src.SecretKeyRef != nil:
+				keys = append(keys, path.Join("Secret", src.SecretKeyRef.Name))
+			case src.ConfigMapKeyRef != nil:
+				keys = append(keys, path.Join("ConfigMap", src.ConfigMapKeyRef.Name))
+			}
+		}
+		return keys
+	}
+}
@@ -58,6 +58,11 @@ func init() {
 	}()
 }
 
+// AddDebugHandler serves the given handler at /debug/{name} on the metrics server.
+func AddDebugHandler(mgr ctrl.Manager, name string, handler http.Handler) error {
+	return mgr.AddMetricsServerExtraHandler("/debug/"+name, handler)
+}
+
 func New(logger logr.Logger, opts *Options) (ctrl.Manager, error) {
 	return newMgr(logger, opts, true, false)
 }
@@ -185,6 +190,11 @@ func newMgr(logger logr.Logger, opts *Options, isController, isReconciler bool)
 			return nil, err
 		}
 
+		err = mgr.GetFieldIndexer().IndexField(context.Background(), &apiv1.Composition{}, IdxCompositionsByEnvRef, indexEnvRefs())
+		if err != nil {
+			return nil, err
+		}
+
 		err = mgr.GetFieldIndexer().IndexField(context.Background(), &apiv1.ResourceSlice{}, IdxResourceSlicesByComposition, indexController())
 		if err != nil {
 			return nil, err
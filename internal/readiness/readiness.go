@@ -26,6 +26,16 @@ func NewEnv() (*Env, error) {
 	return &Env{cel: ce}, nil
 }
 
+// Compile parses the given CEL expression in the context of the environment,
+// and returns a program that can be evaluated against resources bound to `self`.
+func (e *Env) Compile(expr string) (cel.Program, error) {
+	ast, iss := e.cel.Compile(expr)
+	if iss != nil && iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	return e.cel.Program(ast, cel.InterruptCheckFrequency(10))
+}
+
 // Check represents a parsed readiness check CEL expression.
 type Check struct {
 	Name    string
@@ -35,11 +45,7 @@ type Check struct {
 // ParseCheck parses the given CEL expression in the context of an environment,
 // and returns a reusable execution handle.
 func ParseCheck(env *Env, expr string) (*Check, error) {
-	ast, iss := env.cel.Compile(expr)
-	if iss != nil && iss.Err() != nil {
-		return nil, iss.Err()
-	}
-	prgm, err := env.cel.Program(ast, cel.InterruptCheckFrequency(10))
+	prgm, err := env.Compile(expr)
 	if err != nil {
 		return nil, err
 	}
@@ -3,6 +3,7 @@ package reconstitution
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/go-logr/logr"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -136,6 +137,18 @@ func (r *controller) HandleReadinessTransition(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, nil
 	}
 
+	// Syntheses that reuse the output of the previous synthesis share its slices,
+	// so the synthesis that created the slice might not be cached anymore
+	synUUID := slice.Spec.SynthesisUUID
+	comp := &apiv1.Composition{}
+	err = r.client.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: slice.Namespace}, comp)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(fmt.Errorf("getting composition: %w", err))
+	}
+	if syn := comp.Status.CurrentSynthesis; syn != nil && slices.ContainsFunc(syn.ResourceSlices, func(ref *apiv1.ResourceSliceRef) bool { return ref.Name == slice.Name }) {
+		synUUID = syn.UUID
+	}
+
 	for i, res := range slice.Status.Resources {
 		if res.Ready == nil {
 			continue // only care about resources that have become ready
@@ -151,7 +164,7 @@ func (r *controller) HandleReadinessTransition(ctx context.Context, req ctrl.Req
 			return ctrl.Result{}, nil
 		}
 
-		synRef := &SynthesisRef{CompositionName: owner.Name, Namespace: req.Namespace, UUID: slice.Spec.SynthesisUUID}
+		synRef := &SynthesisRef{CompositionName: owner.Name, Namespace: req.Namespace, UUID: synUUID}
 		resources := r.Cache.RangeByReadinessGroup(ctx, synRef, res.ReadinessGroup, RangeAsc)
 		if res.DefinedGroupKind != nil {
 			resources = append(resources, r.Cache.getByGK(synRef, *res.DefinedGroupKind)...)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -17,10 +18,12 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
 	"k8s.io/kubectl/pkg/scheme"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	smdschema "sigs.k8s.io/structured-merge-diff/v4/schema"
 	"sigs.k8s.io/structured-merge-diff/v4/typed"
@@ -61,8 +64,20 @@ type Resource struct {
 	ReadinessChecks   readiness.Checks
 	Patch             jsonpatch.Patch
 	DisableUpdates    bool
+	ObserveOnly       bool
+	Denied            bool // not allowed by the allowlist recorded on the slice
+	Recreate          bool // recreate the resource when updates are rejected for changing immutable fields
 	ReadinessGroup    int
 
+	// IgnoredFields are set when the resource is created, but not when it's updated.
+	// Each element is the path of a field e.g. ["spec", "replicas"].
+	IgnoredFields [][]string
+
+	// ServerSideApply and ApplyConflicts are the server-side apply settings recorded on the slice,
+	// unless they're overridden by the resource's annotations.
+	ServerSideApply *bool
+	ApplyConflicts  apiv1.ConflictPolicy
+
 	// DefinedGroupKind is set on CRDs to represent the resource type they define.
 	DefinedGroupKind *schema.GroupKind
 
@@ -158,10 +173,17 @@ func (r *Resource) Merge(ctx context.Context, old *Resource, current *unstructur
 
 		var prevJS []byte
 		if old != nil {
-			prevJS = []byte(old.Manifest.Manifest)
+			prevJS, err = r.removeIgnoredFieldsJSON([]byte(old.Manifest.Manifest))
+			if err != nil {
+				return nil, false, fmt.Errorf("removing ignored fields from old version: %w", err)
+			}
+		}
+		newJS, err := r.removeIgnoredFieldsJSON([]byte(r.Manifest.Manifest))
+		if err != nil {
+			return nil, false, fmt.Errorf("removing ignored fields from new version: %w", err)
 		}
 
-		patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(prevJS, []byte(r.Manifest.Manifest), currentJS)
+		patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(prevJS, newJS, currentJS)
 		if err != nil {
 			return nil, false, fmt.Errorf("building merge patch: %w", err)
 		}
@@ -184,7 +206,7 @@ func (r *Resource) Merge(ctx context.Context, old *Resource, current *unstructur
 
 	// Convert to SMD values
 	currentVal := value.NewValueInterface(current.Object)
-	typedNew, err := typed.AsTyped(r.value, schem, *typeref)
+	typedNew, err := typed.AsTyped(r.withoutIgnoredFields(r.value), schem, *typeref)
 	if err != nil {
 		return nil, false, fmt.Errorf("converting new version to typed: %w", err)
 	}
@@ -201,7 +223,7 @@ func (r *Resource) Merge(ctx context.Context, old *Resource, current *unstructur
 
 	// Prune properties that were present in the old state but not the new
 	if old != nil {
-		typedOld, err := typed.AsTyped(old.value, schem, *typeref)
+		typedOld, err := typed.AsTyped(r.withoutIgnoredFields(old.value), schem, *typeref)
 		if err != nil {
 			return nil, false, fmt.Errorf("converting old version to typed: %w", err)
 		}
@@ -225,6 +247,37 @@ func (r *Resource) Merge(ctx context.Context, old *Resource, current *unstructur
 	return copy, true, nil
 }
 
+// removeIgnoredFields removes the resource's ignored fields from the given object, in place.
+func (r *Resource) removeIgnoredFields(obj map[string]any) {
+	for _, path := range r.IgnoredFields {
+		unstructured.RemoveNestedField(obj, path...)
+	}
+}
+
+// withoutIgnoredFields returns a copy of the given value without the resource's ignored fields.
+// The fields ignored by the new version are also removed from the old version, so fields aren't pruned when they become ignored.
+func (r *Resource) withoutIgnoredFields(val value.Value) value.Value {
+	if len(r.IgnoredFields) == 0 {
+		return val
+	}
+	obj := runtime.DeepCopyJSON(val.Unstructured().(map[string]any))
+	r.removeIgnoredFields(obj)
+	return value.NewValueInterface(obj)
+}
+
+func (r *Resource) removeIgnoredFieldsJSON(js []byte) ([]byte, error) {
+	if len(r.IgnoredFields) == 0 {
+		return js, nil
+	}
+	obj := map[string]any{}
+	err := json.Unmarshal(js, &obj)
+	if err != nil {
+		return nil, err
+	}
+	r.removeIgnoredFields(obj)
+	return json.Marshal(obj)
+}
+
 // compareWithScheme uses logic registered with the global scheme to compare two resources.
 // This is necessary for cases in which resources have special comparison logic that isn't represented by the openapi spec.
 // For example: resource quantities.
@@ -296,6 +349,12 @@ func NewResource(ctx context.Context, renv *readiness.Env, slice *apiv1.Resource
 		res.Patch = obj.Patch.Ops
 	}
 
+	res.Denied = !slice.Spec.Allowlist.Allows(res.GVK.GroupKind(), res.Ref.Namespace, res.Ref.Name)
+	if ssa := slice.Spec.ServerSideApply; ssa != nil {
+		res.ServerSideApply = ptr.To(true)
+		res.ApplyConflicts = ssa.Conflicts
+	}
+
 	if res.GVK.Group == "apiextensions.k8s.io" && res.GVK.Kind == "CustomResourceDefinition" {
 		res.DefinedGroupKind = &schema.GroupKind{}
 		res.DefinedGroupKind.Group, _, _ = unstructured.NestedString(parsed.Object, "spec", "group")
@@ -319,6 +378,55 @@ func NewResource(ctx context.Context, renv *readiness.Env, slice *apiv1.Resource
 	res.DisableUpdates = anno[disableUpdatesKey] == "true"
 	delete(anno, disableUpdatesKey)
 
+	const updateStrategyKey = "eno.azure.io/update-strategy"
+	switch anno[updateStrategyKey] {
+	case "":
+	case "recreate":
+		res.Recreate = true
+	default:
+		logger.V(0).Info("invalid update strategy - ignoring")
+	}
+	delete(anno, updateStrategyKey)
+
+	const observeOnlyKey = "eno.azure.io/observe-only"
+	res.ObserveOnly = anno[observeOnlyKey] == "true"
+	delete(anno, observeOnlyKey)
+
+	const ignoreFieldsKey = "eno.azure.io/ignore-fields"
+	for _, str := range strings.Split(anno[ignoreFieldsKey], ",") {
+		if strings.TrimSpace(str) == "" {
+			continue
+		}
+		path := parseFieldPath(str)
+		if slices.Contains(path, "") {
+			logger.V(0).Info("invalid ignored field path - ignoring", "path", str)
+			continue
+		}
+		res.IgnoredFields = append(res.IgnoredFields, path)
+	}
+	delete(anno, ignoreFieldsKey)
+
+	const serverSideApplyKey = "eno.azure.io/server-side-apply"
+	if str, ok := anno[serverSideApplyKey]; ok {
+		ssa, err := strconv.ParseBool(str)
+		if err != nil {
+			logger.V(0).Info("invalid server-side apply setting - ignoring")
+		} else {
+			res.ServerSideApply = &ssa
+		}
+	}
+	delete(anno, serverSideApplyKey)
+
+	const applyConflictsKey = "eno.azure.io/server-side-apply-conflicts"
+	switch policy := apiv1.ConflictPolicy(anno[applyConflictsKey]); policy {
+	case "":
+	case apiv1.ConflictPolicyForce, apiv1.ConflictPolicyReport:
+		res.ApplyConflicts = policy
+	default:
+		logger.V(0).Info("invalid server-side apply conflict policy - ignoring")
+	}
+	delete(anno, applyConflictsKey)
+
 	const readinessGroupKey = "eno.azure.io/readiness-group"
 	rg, err := strconv.ParseInt(anno[readinessGroupKey], 10, 64)
 	if anno[readinessGroupKey] != "" && err != nil {
@@ -352,6 +460,21 @@ func NewResource(ctx context.Context, renv *readiness.Env, slice *apiv1.Resource
 	return res, nil
 }
 
+// parseFieldPath parses a field path in either dot notation e.g. "spec.replicas",
+// or JSON pointer notation e.g. "/metadata/labels/app.kubernetes.io~1name".
+func parseFieldPath(str string) []string {
+	str = strings.TrimSpace(str)
+	if !strings.HasPrefix(str, "/") {
+		return strings.Split(strings.TrimPrefix(str, "."), ".")
+	}
+
+	path := strings.Split(str[1:], "/")
+	for i, elem := range path {
+		path[i] = strings.ReplaceAll(strings.ReplaceAll(elem, "~1", "/"), "~0", "~")
+	}
+	return path
+}
+
 // Less returns true when r < than.
 // Used to establish determinstic ordering for conflicting resources.
 func (r *Resource) Less(than *Resource) bool {
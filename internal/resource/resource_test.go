@@ -17,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/kube-openapi/pkg/schemaconv"
 	"k8s.io/kube-openapi/pkg/util/proto"
+	"k8s.io/utils/ptr"
 	smdschema "sigs.k8s.io/structured-merge-diff/v4/schema"
 )
 
@@ -38,7 +39,9 @@ var newResourceTests = []struct {
 					"eno.azure.io/readiness-group": "250",
 					"eno.azure.io/readiness": "true",
 					"eno.azure.io/readiness-test": "false",
-					"eno.azure.io/disable-updates": "true"
+					"eno.azure.io/disable-updates": "true",
+					"eno.azure.io/observe-only": "true",
+					"eno.azure.io/update-strategy": "recreate"
 				}
 			}
 		}`,
@@ -53,9 +56,68 @@ var newResourceTests = []struct {
 				Kind:      "ConfigMap",
 			}, r.Ref)
 			assert.True(t, r.DisableUpdates)
+			assert.True(t, r.ObserveOnly)
+			assert.True(t, r.Recreate)
 			assert.Equal(t, int(250), r.ReadinessGroup)
 		},
 	},
+	{
+		Name: "ignore-fields",
+		Manifest: `{
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {
+				"name": "foo",
+				"annotations": {
+					"eno.azure.io/ignore-fields": "spec.replicas, .metadata.labels.foo,/metadata/labels/app.kubernetes.io~1name,spec..invalid"
+				}
+			}
+		}`,
+		Assert: func(t *testing.T, r *Resource) {
+			assert.Equal(t, [][]string{
+				{"spec", "replicas"},
+				{"metadata", "labels", "foo"},
+				{"metadata", "labels", "app.kubernetes.io/name"},
+			}, r.IgnoredFields)
+		},
+	},
+	{
+		Name: "server-side-apply",
+		Manifest: `{
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {
+				"name": "foo",
+				"annotations": {
+					"eno.azure.io/server-side-apply": "false",
+					"eno.azure.io/server-side-apply-conflicts": "Report"
+				}
+			}
+		}`,
+		Assert: func(t *testing.T, r *Resource) {
+			require.NotNil(t, r.ServerSideApply)
+			assert.False(t, *r.ServerSideApply)
+			assert.Equal(t, apiv1.ConflictPolicyReport, r.ApplyConflicts)
+		},
+	},
+	{
+		Name: "invalid-server-side-apply",
+		Manifest: `{
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {
+				"name": "foo",
+				"annotations": {
+					"eno.azure.io/server-side-apply": "maybe",
+					"eno.azure.io/server-side-apply-conflicts": "Ignore"
+				}
+			}
+		}`,
+		Assert: func(t *testing.T, r *Resource) {
+			assert.Nil(t, r.ServerSideApply)
+			assert.Empty(t, r.ApplyConflicts)
+		},
+	},
 	{
 		Name: "zero-readiness-group",
 		Manifest: `{
@@ -100,6 +162,7 @@ var newResourceTests = []struct {
 		}`,
 		Assert: func(t *testing.T, r *Resource) {
 			assert.Equal(t, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, r.GVK)
+			assert.False(t, r.Recreate)
 			assert.Len(t, r.ReadinessChecks, 0)
 			assert.Nil(t, r.ReconcileInterval)
 			assert.Equal(t, Ref{
@@ -210,6 +273,69 @@ func TestNewResource(t *testing.T) {
 	}
 }
 
+func TestNewResourceDenied(t *testing.T) {
+	ctx := context.Background()
+	renv, err := readiness.NewEnv()
+	require.NoError(t, err)
+
+	slice := &apiv1.ResourceSlice{
+		Spec: apiv1.ResourceSliceSpec{
+			Resources: []apiv1.Manifest{
+				{Manifest: `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "allowed", "namespace": "default"}}`},
+				{Manifest: `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "denied", "namespace": "other"}}`},
+			},
+			Allowlist: &apiv1.ResourceAllowlist{Namespaces: []string{"default"}},
+		},
+	}
+
+	r, err := NewResource(ctx, renv, slice, 0)
+	require.NoError(t, err)
+	assert.False(t, r.Denied)
+
+	r, err = NewResource(ctx, renv, slice, 1)
+	require.NoError(t, err)
+	assert.True(t, r.Denied)
+
+	// Anything is allowed without an allowlist
+	slice.Spec.Allowlist = nil
+	r, err = NewResource(ctx, renv, slice, 1)
+	require.NoError(t, err)
+	assert.False(t, r.Denied)
+}
+
+func TestNewResourceServerSideApply(t *testing.T) {
+	ctx := context.Background()
+	renv, err := readiness.NewEnv()
+	require.NoError(t, err)
+
+	slice := &apiv1.ResourceSlice{
+		Spec: apiv1.ResourceSliceSpec{
+			Resources: []apiv1.Manifest{
+				{Manifest: `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "default"}}`},
+				{Manifest: `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "override", "annotations": {"eno.azure.io/server-side-apply": "false", "eno.azure.io/server-side-apply-conflicts": "Force"}}}`},
+			},
+			ServerSideApply: &apiv1.ServerSideApply{Conflicts: apiv1.ConflictPolicyReport},
+		},
+	}
+
+	// The slice's configuration is used by default
+	r, err := NewResource(ctx, renv, slice, 0)
+	require.NoError(t, err)
+	assert.Equal(t, ptr.To(true), r.ServerSideApply)
+	assert.Equal(t, apiv1.ConflictPolicyReport, r.ApplyConflicts)
+
+	// ...and can be overridden by annotations
+	r, err = NewResource(ctx, renv, slice, 1)
+	require.NoError(t, err)
+	assert.Equal(t, ptr.To(false), r.ServerSideApply)
+	assert.Equal(t, apiv1.ConflictPolicyForce, r.ApplyConflicts)
+
+	slice.Spec.ServerSideApply = nil
+	r, err = NewResource(ctx, renv, slice, 0)
+	require.NoError(t, err)
+	assert.Nil(t, r.ServerSideApply)
+}
+
 func TestMergeBasics(t *testing.T) {
 	testMergeBasics(t, "io.k8s.api.apps.v1.Deployment")
 }
@@ -352,6 +478,121 @@ func testMergeBasics(t *testing.T, schemaName string) {
 	assert.Nil(t, merged)
 }
 
+func TestMergeIgnoredFields(t *testing.T) {
+	testMergeIgnoredFields(t, "io.k8s.api.apps.v1.Deployment")
+}
+
+func TestMergeIgnoredFieldsNoSchema(t *testing.T) {
+	testMergeIgnoredFields(t, "")
+}
+
+func testMergeIgnoredFields(t *testing.T, schemaName string) {
+	t.Helper()
+	ctx := context.Background()
+
+	sg := newTestSchemaGetter(t, schemaName)
+
+	renv, err := readiness.NewEnv()
+	require.NoError(t, err)
+
+	newSlice := &apiv1.ResourceSlice{
+		Spec: apiv1.ResourceSliceSpec{
+			Resources: []apiv1.Manifest{{
+				Manifest: `{
+				  "apiVersion": "apps/v1",
+				  "kind": "Deployment",
+				  "metadata": {
+				    "name": "foo",
+				    "annotations": {
+				      "eno.azure.io/ignore-fields": "spec.replicas,spec.strategy"
+				    }
+				  },
+				  "spec": {
+				    "replicas": 2,
+				    "template": {
+				      "spec": {
+				        "serviceAccountName": "updated"
+				      }
+				    }
+				  }
+				}`,
+			}},
+		},
+	}
+	newState, err := NewResource(ctx, renv, newSlice, 0)
+	require.NoError(t, err)
+
+	// The old version sets a field that is ignored (rather than removed) by the new version
+	oldSlice := &apiv1.ResourceSlice{
+		Spec: apiv1.ResourceSliceSpec{
+			Resources: []apiv1.Manifest{{
+				Manifest: `{
+				  "apiVersion": "apps/v1",
+				  "kind": "Deployment",
+				  "metadata": {
+				    "name": "foo"
+				  },
+				  "spec": {
+				    "replicas": 2,
+				    "strategy": {
+				      "type": "RollingUpdate"
+				    },
+				    "template": {
+				      "spec": {
+				        "serviceAccountName": "original"
+				      }
+				    }
+				  }
+				}`,
+			}},
+		},
+	}
+	oldState, err := NewResource(ctx, renv, oldSlice, 0)
+	require.NoError(t, err)
+
+	// The annotations are set when the resource is created
+	annotations := map[string]any{"eno.azure.io/ignore-fields": "spec.replicas,spec.strategy"}
+	current := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "foo", "resourceVersion": "1", "annotations": annotations},
+		"spec": map[string]any{
+			"replicas": int64(5),
+			"strategy": map[string]any{"type": "RollingUpdate"},
+			"template": map[string]any{
+				"spec": map[string]any{
+					"serviceAccountName": "original",
+				},
+			},
+		},
+	}}
+
+	expected := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "foo", "resourceVersion": "1", "annotations": annotations},
+		"spec": map[string]any{
+			"replicas": int64(5),
+			"strategy": map[string]any{"type": "RollingUpdate"},
+			"template": map[string]any{
+				"spec": map[string]any{
+					"serviceAccountName": "updated",
+				},
+			},
+		},
+	}}
+
+	merged, typed, err := newState.Merge(ctx, oldState, current, sg)
+	require.NoError(t, err)
+	assert.Equal(t, schemaName != "", typed)
+	require.Equal(t, expected, merged)
+
+	// Ignored fields alone don't require an update
+	merged, _, err = newState.Merge(ctx, oldState, expected, sg)
+	require.NoError(t, err)
+	assert.Nil(t, merged)
+}
+
 func TestResourceOrdering(t *testing.T) {
 	resources := []*Resource{
 		{Manifest: &apiv1.Manifest{Manifest: "a"}},
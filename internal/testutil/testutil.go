@@ -47,7 +47,7 @@ func NewClientWithInterceptors(t testing.TB, ict *interceptor.Funcs, objs ...cli
 	builder := fake.NewClientBuilder().
 		WithScheme(scheme).
 		WithObjects(objs...).
-		WithStatusSubresource(&apiv1.ResourceSlice{}, &apiv1.Composition{}, &apiv1.Symphony{})
+		WithStatusSubresource(&apiv1.ResourceSlice{}, &apiv1.Composition{}, &apiv1.Symphony{}, &apiv1.Synthesizer{})
 
 	if ict != nil {
 		builder.WithInterceptorFuncs(*ict)
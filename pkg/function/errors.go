@@ -0,0 +1,10 @@
+package function
+
+// TerminalExitCode is the exit code synthesizers can use to signal that a failure is terminal.
+// Terminal failures mark the synthesis as failed instead of retrying it.
+// Any other non-zero exit code is retried.
+const TerminalExitCode = 78
+
+// RetryTag can be set to "true" on error results to signal that they are transient.
+// Syntheses with retryable error results are retried instead of failing.
+const RetryTag = "eno.azure.io/retry"
@@ -50,6 +50,26 @@ func ReadInput[T client.Object](ir *InputReader, key string, out T) error {
 	return nil
 }
 
+// ReadValues decodes the composition's values into out.
+// Values are passed to synthesizers as the functionConfig of the input ResourceList.
+// out is left unchanged when the composition doesn't set any values.
+func ReadValues[T any](ir *InputReader, out *T) error {
+	fc := ir.resources.FunctionConfig
+	if fc == nil || fc.Object["values"] == nil {
+		return nil
+	}
+
+	js, err := json.Marshal(fc.Object["values"])
+	if err != nil {
+		return fmt.Errorf("encoding values: %w", err)
+	}
+	err = json.Unmarshal(js, out)
+	if err != nil {
+		return fmt.Errorf("decoding values: %w", err)
+	}
+	return nil
+}
+
 func (i *InputReader) All() map[string]*unstructured.Unstructured {
 	m := map[string]*unstructured.Unstructured{}
 	for _, o := range i.resources.Items {
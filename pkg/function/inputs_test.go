@@ -25,3 +25,34 @@ func TestInputReader(t *testing.T) {
 	err = ReadInput(r, "bar", cm)
 	require.EqualError(t, err, "input \"bar\" was not found")
 }
+
+func TestReadValues(t *testing.T) {
+	type values struct {
+		Replicas int               `json:"replicas"`
+		Image    string            `json:"image"`
+		Labels   map[string]string `json:"labels"`
+	}
+
+	input := bytes.NewBufferString(`{ "items": [], "functionConfig": { "apiVersion": "eno.azure.io/v1", "kind": "CompositionValues", "values": { "replicas": 3, "labels": { "foo": "bar" } } } }`)
+	r, err := NewInputReader(input)
+	require.NoError(t, err)
+
+	out := &values{Image: "default-image"}
+	require.NoError(t, ReadValues(r, out))
+	assert.Equal(t, &values{Replicas: 3, Image: "default-image", Labels: map[string]string{"foo": "bar"}}, out)
+
+	// Wrong type
+	input = bytes.NewBufferString(`{ "items": [], "functionConfig": { "apiVersion": "eno.azure.io/v1", "kind": "CompositionValues", "values": { "replicas": "three" } } }`)
+	r, err = NewInputReader(input)
+	require.NoError(t, err)
+	assert.ErrorContains(t, ReadValues(r, out), "decoding values")
+
+	// Missing
+	input = bytes.NewBufferString(`{ "items": [] }`)
+	r, err = NewInputReader(input)
+	require.NoError(t, err)
+
+	out = &values{Image: "default-image"}
+	require.NoError(t, ReadValues(r, out))
+	assert.Equal(t, &values{Image: "default-image"}, out)
+}